@@ -0,0 +1,268 @@
+// Package arbitrage detects arbitrage cycles across pools and sizes them by quoting against the pools.
+package arbitrage
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/routing"
+)
+
+var ErrNotMidPriceGraph = errors.New("arbitrage needs a graph weighted by mid-price")
+
+// epsilon ignores cycles whose log rate is within floating point noise of zero
+const epsilon = 1e-12
+
+// maxDoublings bounds the search for an input that makes a profit
+const maxDoublings = 128
+
+// Opportunity is a cycle sized to the input that maximizes its profit
+type Opportunity struct {
+	Cycle     routing.Path // starts and ends with the token of the amounts
+	LogRate   float64      // the log of the cycle's rate for infinitesimal amounts, net of fees
+	AmountIn  *entities.CurrencyAmount
+	AmountOut *entities.CurrencyAmount
+	Profit    *entities.CurrencyAmount // AmountOut - AmountIn, net of all swap fees
+	Swaps     []*entities.SwapResult   // the swap of each hop
+}
+
+type tokenKey struct {
+	chainID uint
+	address string
+}
+
+func keyOf(token *entities.Token) tokenKey {
+	return tokenKey{chainID: token.ChainId(), address: token.Address}
+}
+
+/**
+ * Finds negative cycles of a mid-price weighted graph with Bellman-Ford, i.e. cycles of pools whose rates multiply to
+ * more than one net of fees. Pools without liquidity are ignored. A cycle starts at the edge with the lowest pool ID.
+ * Not every negative cycle is found when cycles share tokens, but at least one is whenever one exists.
+ * @param g a graph weighted by routing.WeightMidPrice
+ */
+func FindCycles(g *routing.Graph) ([]routing.Path, error) {
+	if g.Weighting() != routing.WeightMidPrice {
+		return nil, ErrNotMidPriceGraph
+	}
+	tokens := g.Tokens()
+	var edges []routing.Edge
+	for _, token := range tokens {
+		for _, e := range g.Edges(token) {
+			if e.Pool.Liquidity.Sign() > 0 {
+				edges = append(edges, e)
+			}
+		}
+	}
+
+	// every token starts at distance zero, as if from a virtual source
+	dist := make(map[tokenKey]float64, len(tokens))
+	pred := make(map[tokenKey]routing.Edge, len(tokens))
+	var relaxed []tokenKey
+	for i := 0; i < len(tokens); i++ {
+		relaxed = relaxed[:0]
+		for _, e := range edges {
+			in, out := keyOf(e.TokenIn), keyOf(e.TokenOut)
+			if d := dist[in] + e.Weight; d < dist[out]-epsilon {
+				dist[out] = d
+				pred[out] = e
+				relaxed = append(relaxed, out)
+			}
+		}
+		if len(relaxed) == 0 {
+			return nil, nil
+		}
+	}
+
+	// tokens still relaxed after len(tokens) rounds lead back to a negative cycle through their predecessors
+	var cycles []routing.Path
+	seen := make(map[string]bool)
+	for _, token := range relaxed {
+		for i := 0; i < len(tokens); i++ {
+			token = keyOf(pred[token].TokenIn)
+		}
+		var cycle []routing.Edge
+		for at := token; ; {
+			e := pred[at]
+			cycle = append(cycle, e)
+			at = keyOf(e.TokenIn)
+			if at == token {
+				break
+			}
+		}
+		cycle = canonical(cycle)
+		if id := cycleID(cycle); !seen[id] {
+			seen[id] = true
+			cycles = append(cycles, newCycle(cycle))
+		}
+	}
+	sort.SliceStable(cycles, func(i, j int) bool { return cycles[i].Weight < cycles[j].Weight })
+	return cycles, nil
+}
+
+// canonical reverses the predecessor order of a cycle and rotates it to start at the edge with the lowest pool ID
+func canonical(reversed []routing.Edge) []routing.Edge {
+	n := len(reversed)
+	start := 0
+	for i := 1; i < n; i++ {
+		if reversed[i].ID < reversed[start].ID {
+			start = i
+		}
+	}
+	cycle := make([]routing.Edge, 0, n)
+	for i := 0; i < n; i++ {
+		cycle = append(cycle, reversed[(start-i+n)%n])
+	}
+	return cycle
+}
+
+func cycleID(cycle []routing.Edge) string {
+	var id string
+	for _, e := range cycle {
+		id += e.ID + ">" + e.TokenOut.Address + ";"
+	}
+	return id
+}
+
+func newCycle(edges []routing.Edge) routing.Path {
+	p := routing.Path{Edges: edges}
+	for _, e := range edges {
+		p.Weight += e.Weight
+	}
+	return p
+}
+
+/**
+ * Finds the arbitrage cycles of a graph and sizes each of them, see FindCycles and Size. Cycles whose profit
+ * vanishes once sized, e.g. to rounding, are left out. The opportunities are ordered by log rate, best first.
+ * @param g a graph weighted by routing.WeightMidPrice
+ * @param maxInput caps the input amount of each cycle, in raw amounts of its start token; nil for no cap
+ */
+func Detect(g *routing.Graph, maxInput *big.Int) ([]Opportunity, error) {
+	cycles, err := FindCycles(g)
+	if err != nil {
+		return nil, err
+	}
+	var opportunities []Opportunity
+	for _, cycle := range cycles {
+		o, err := Size(cycle, maxInput)
+		if err != nil {
+			return nil, err
+		}
+		if o != nil {
+			opportunities = append(opportunities, *o)
+		}
+	}
+	return opportunities, nil
+}
+
+/**
+ * Sizes a cycle by searching for the input that maximizes the profit of running Pool.GetOutputAmount along it. The
+ * profit is assumed to be unimodal in the input, which holds for cycles through distinct pools. Inputs the pools
+ * cannot fill are not considered.
+ * @param cycle a path that ends with its start token
+ * @param maxInput caps the input, in raw amounts of the start token; nil for no cap
+ * @returns nil if no input makes a profit, including when the cap is zero or less
+ */
+func Size(cycle routing.Path, maxInput *big.Int) (*Opportunity, error) {
+	if maxInput != nil && maxInput.Sign() <= 0 {
+		return nil, nil
+	}
+	start := cycle.Edges[0].TokenIn
+	profitAt := func(x *big.Int) (*big.Int, error) {
+		return cycleProfit(cycle, x)
+	}
+	better := func(a, b *big.Int) bool {
+		return a != nil && (b == nil || a.Cmp(b) > 0)
+	}
+
+	// double the input until the profit shrinks, the optimum is then between a quarter and all of it. Rounding makes
+	// tiny inputs lose, so keep doubling until there is a profit.
+	hi := big.NewInt(1)
+	best, err := profitAt(hi)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < maxDoublings && (maxInput == nil || hi.Cmp(maxInput) < 0); i++ {
+		next := new(big.Int).Lsh(hi, 1)
+		if maxInput != nil && next.Cmp(maxInput) > 0 {
+			next.Set(maxInput)
+		}
+		profit, err := profitAt(next)
+		if err != nil {
+			return nil, err
+		}
+		hi = next
+		if profit == nil || (!better(profit, best) && best != nil && best.Sign() > 0) {
+			break
+		}
+		if better(profit, best) {
+			best = profit
+		}
+	}
+
+	// ternary search for the peak
+	lo := new(big.Int).Rsh(hi, 2)
+	three := big.NewInt(3)
+	for new(big.Int).Sub(hi, lo).Cmp(three) > 0 {
+		third := new(big.Int).Div(new(big.Int).Sub(hi, lo), three)
+		m1 := new(big.Int).Add(lo, third)
+		m2 := new(big.Int).Sub(hi, third)
+		p1, err := profitAt(m1)
+		if err != nil {
+			return nil, err
+		}
+		p2, err := profitAt(m2)
+		if err != nil {
+			return nil, err
+		}
+		if better(p2, p1) {
+			lo = m1
+		} else {
+			hi = m2
+		}
+	}
+	var amountIn, profit *big.Int
+	for x := new(big.Int).Set(lo); x.Cmp(hi) <= 0; x = new(big.Int).Add(x, big.NewInt(1)) {
+		if x.Sign() == 0 {
+			continue
+		}
+		p, err := profitAt(x)
+		if err != nil {
+			return nil, err
+		}
+		if better(p, profit) {
+			amountIn, profit = x, p
+		}
+	}
+	if profit == nil || profit.Sign() <= 0 {
+		return nil, nil
+	}
+
+	out, swaps, err := cycle.GetOutputAmount(entities.FromRawAmount(start, amountIn))
+	if err != nil {
+		return nil, err
+	}
+	return &Opportunity{
+		Cycle:     cycle,
+		LogRate:   -cycle.Weight,
+		AmountIn:  entities.FromRawAmount(start, amountIn),
+		AmountOut: out,
+		Profit:    entities.FromRawAmount(start, profit),
+		Swaps:     swaps,
+	}, nil
+}
+
+// cycleProfit returns the profit of running x along the cycle, nil if the pools cannot fill it
+func cycleProfit(cycle routing.Path, x *big.Int) (*big.Int, error) {
+	out, _, err := cycle.GetOutputAmount(entities.FromRawAmount(cycle.Edges[0].TokenIn, x), entities.WithRequireFullFill())
+	if errors.Is(err, entities.ErrPartialFill) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Sub(out.Quotient(), x), nil
+}
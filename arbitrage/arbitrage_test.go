@@ -0,0 +1,100 @@
+package arbitrage
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/internal/testpools"
+	"github.com/mythril-labs/clmm-sui-sdk/registry"
+	"github.com/mythril-labs/clmm-sui-sdk/routing"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	SUI  = testpools.SUI
+	USDC = testpools.USDC
+	USDT = testpools.USDT
+)
+
+func ids(p routing.Path) []string {
+	var ids []string
+	for _, e := range p.Edges {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestFindCycles(t *testing.T) {
+	balanced := []registry.Entry{
+		{ID: "0x1", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e12)},
+		{ID: "0x2", Pool: testpools.FullRange(USDC, USDT, constants.FeeLowest, 0, 1e12)},
+		{ID: "0x3", Pool: testpools.FullRange(SUI, USDT, constants.FeeMedium, 10, 1e12)},
+	}
+	cycles, err := FindCycles(routing.NewGraph(balanced, routing.WeightMidPrice))
+	assert.NoError(t, err)
+	assert.Empty(t, cycles, "fees outweigh the price difference")
+
+	_, err = FindCycles(routing.NewGraph(balanced, routing.WeightLiquidity))
+	assert.ErrorIs(t, err, ErrNotMidPriceGraph)
+
+	// SUI sells for 1.22 USDT but only 1 USDC
+	skewed := append(balanced[:2:2], registry.Entry{ID: "0x3", Pool: testpools.FullRange(SUI, USDT, constants.FeeMedium, 2000, 1e12)})
+	cycles, err = FindCycles(routing.NewGraph(skewed, routing.WeightMidPrice))
+	assert.NoError(t, err)
+	if assert.Len(t, cycles, 1) {
+		assert.Equal(t, []string{"0x1", "0x3", "0x2"}, ids(cycles[0]))
+		assert.Equal(t, []*entities.Token{USDC, SUI, USDT, USDC}, cycles[0].Tokens())
+		assert.InDelta(t, 2000*math.Log(1.0001)+2*math.Log(1-0.0025)+math.Log(1-0.0001), -cycles[0].Weight, 1e-9)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	pools := []registry.Entry{
+		{ID: "0x1", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e12)},
+		{ID: "0x2", Pool: testpools.FullRange(USDC, USDT, constants.FeeLowest, 0, 1e12)},
+		{ID: "0x3", Pool: testpools.FullRange(SUI, USDT, constants.FeeMedium, 2000, 1e12)},
+	}
+	g := routing.NewGraph(pools, routing.WeightMidPrice)
+	opportunities, err := Detect(g, nil)
+	assert.NoError(t, err)
+	if !assert.Len(t, opportunities, 1) {
+		return
+	}
+	o := opportunities[0]
+	assert.True(t, o.AmountIn.Currency.Equal(USDC))
+	assert.Equal(t, o.AmountOut.Subtract(o.AmountIn).Quotient(), o.Profit.Quotient())
+	assert.Equal(t, 1, o.Profit.Quotient().Sign())
+	assert.Len(t, o.Swaps, 3)
+	assert.Equal(t, -o.Cycle.Weight, o.LogRate)
+
+	// the profit is lower on either side of the optimum
+	for _, factor := range []float64{0.5, 0.9, 1.1, 2} {
+		x, _ := new(big.Float).Mul(new(big.Float).SetInt(o.AmountIn.Quotient()), big.NewFloat(factor)).Int(nil)
+		out, _, err := o.Cycle.GetOutputAmount(entities.FromRawAmount(USDC, x))
+		assert.NoError(t, err)
+		profit := new(big.Int).Sub(out.Quotient(), x)
+		assert.Equal(t, -1, profit.Cmp(o.Profit.Quotient()), "factor %v", factor)
+	}
+
+	capped, err := Detect(g, big.NewInt(1000))
+	assert.NoError(t, err)
+	if assert.Len(t, capped, 1) {
+		// near the cap, not at it, as rounding dominates tiny swaps
+		assert.LessOrEqual(t, capped[0].AmountIn.Quotient().Int64(), int64(1000))
+		assert.Greater(t, capped[0].AmountIn.Quotient().Int64(), int64(900))
+		assert.Equal(t, -1, capped[0].Profit.Quotient().Cmp(o.Profit.Quotient()))
+	}
+
+	// a cap of zero or less allows no input at all
+	for _, maxInput := range []int64{0, -1} {
+		sized, err := Size(o.Cycle, big.NewInt(maxInput))
+		assert.NoError(t, err)
+		assert.Nil(t, sized, "cap %d", maxInput)
+	}
+	capped, err = Detect(g, big.NewInt(0))
+	assert.NoError(t, err)
+	assert.Empty(t, capped)
+}
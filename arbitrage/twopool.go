@@ -0,0 +1,91 @@
+package arbitrage
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/registry"
+	"github.com/mythril-labs/clmm-sui-sdk/routing"
+)
+
+var (
+	ErrDifferentPairs   = errors.New("pools have different pairs")
+	ErrTokenNotInPools  = errors.New("token is not in the pools")
+	ErrInvalidCurveSize = errors.New("curve points must not be negative")
+)
+
+// ProfitPoint is the profit of an input amount, in raw amounts of the input token
+type ProfitPoint struct {
+	AmountIn *big.Int
+	Profit   *big.Int // nil if the pools cannot fill the input
+}
+
+// TwoPoolOpportunity is an arbitrage between two pools of the same pair, with the profit curve around its optimum
+type TwoPoolOpportunity struct {
+	Opportunity
+	Curve []ProfitPoint // ascending inputs from zero to twice the optimum, the optimum in the middle
+}
+
+/**
+ * Sizes the arbitrage between two pools of the same pair: swapping tokenIn for the other token in the pool where it
+ * sells high, and back in the other pool. The direction follows from the pools' mid-prices net of fees, the input
+ * that maximizes the profit from quoting both swaps, which cross ticks through each pool's TickDataProvider.
+ * @param a one pool of the pair
+ * @param b the other pool of the pair
+ * @param tokenIn the token the arbitrage starts and ends with
+ * @param maxInput caps the input, in raw amounts of tokenIn; nil for no cap
+ * @param curvePoints the number of points of the profit curve on either side of the optimum
+ * @returns nil if no input makes a profit
+ */
+func SizeTwoPools(a, b registry.Entry, tokenIn *entities.Token, maxInput *big.Int, curvePoints int) (*TwoPoolOpportunity, error) {
+	if !a.Pool.Token0.Equal(b.Pool.Token0) || !a.Pool.Token1.Equal(b.Pool.Token1) {
+		if !a.Pool.Token0.Equal(b.Pool.Token1) || !a.Pool.Token1.Equal(b.Pool.Token0) {
+			return nil, ErrDifferentPairs
+		}
+	}
+	if !a.Pool.InvolvesToken(tokenIn) {
+		return nil, ErrTokenNotInPools
+	}
+	if curvePoints < 0 {
+		return nil, ErrInvalidCurveSize
+	}
+
+	// the better of the two cycles: out through a and back through b, or the reverse
+	g := routing.NewGraph([]registry.Entry{a, b}, routing.WeightMidPrice)
+	var cycle *routing.Path
+	for _, out := range g.Edges(tokenIn) {
+		for _, back := range g.Edges(out.TokenOut) {
+			if back.ID == out.ID {
+				continue
+			}
+			if c := newCycle([]routing.Edge{out, back}); c.Weight < -epsilon && (cycle == nil || c.Weight < cycle.Weight) {
+				cycle = &c
+			}
+		}
+	}
+	if cycle == nil {
+		return nil, nil
+	}
+
+	o, err := Size(*cycle, maxInput)
+	if err != nil || o == nil {
+		return nil, err
+	}
+	optimum := o.AmountIn.Quotient()
+	curve := make([]ProfitPoint, 0, 2*curvePoints+1)
+	for i := 0; i <= 2*curvePoints; i++ {
+		x := new(big.Int).Set(optimum)
+		if i != curvePoints {
+			x.Mul(x, big.NewInt(int64(i))).Div(x, big.NewInt(int64(curvePoints)))
+		}
+		profit := new(big.Int)
+		if x.Sign() > 0 {
+			if profit, err = cycleProfit(*cycle, x); err != nil {
+				return nil, err
+			}
+		}
+		curve = append(curve, ProfitPoint{AmountIn: x, Profit: profit})
+	}
+	return &TwoPoolOpportunity{Opportunity: *o, Curve: curve}, nil
+}
@@ -0,0 +1,72 @@
+package arbitrage
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/internal/testpools"
+	"github.com/mythril-labs/clmm-sui-sdk/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeTwoPools(t *testing.T) {
+	cheap := registry.Entry{ID: "0x1", Pool: testpools.FullRange(SUI, USDC, constants.FeeLowest, 0, 1e12)}
+	// concentrated liquidity just below the price, which the arbitrage crosses into
+	dear, err := testpools.FullRange(SUI, USDC, constants.FeeLow, 500, 1e12).ApplyMint(400, 490, big.NewInt(5e12))
+	if !assert.NoError(t, err) {
+		return
+	}
+	expensive := registry.Entry{ID: "0x2", Pool: dear}
+
+	o, err := SizeTwoPools(cheap, expensive, SUI, nil, 4)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, o) {
+		return
+	}
+	// sell SUI where it is dear, buy it back where it is cheap
+	assert.Equal(t, "0x2", o.Cycle.Edges[0].ID)
+	assert.Equal(t, "0x1", o.Cycle.Edges[1].ID)
+	assert.True(t, o.Profit.Currency.Equal(SUI))
+	assert.Equal(t, 1, o.Profit.Quotient().Sign())
+	assert.Greater(t, o.Swaps[0].CrossedTicks, 0)
+
+	assert.Len(t, o.Curve, 9)
+	assert.Equal(t, 0, o.Curve[0].AmountIn.Sign())
+	assert.Equal(t, o.AmountIn.Quotient(), o.Curve[4].AmountIn)
+	assert.Equal(t, o.Profit.Quotient(), o.Curve[4].Profit)
+	assert.Equal(t, new(big.Int).Lsh(o.AmountIn.Quotient(), 1), o.Curve[8].AmountIn)
+	for i, point := range o.Curve {
+		if i != 4 {
+			assert.Equal(t, -1, point.Profit.Cmp(o.Profit.Quotient()), "point %d", i)
+		}
+		if i > 0 && i < 4 {
+			assert.Equal(t, 1, point.Profit.Cmp(o.Curve[i-1].Profit), "the profit grows up to the optimum")
+		}
+		if i > 4 {
+			assert.Equal(t, -1, point.Profit.Cmp(o.Curve[i-1].Profit), "and falls after it")
+		}
+	}
+
+	// the same arbitrage measured in USDC goes the other way round
+	o, err = SizeTwoPools(cheap, expensive, USDC, nil, 0)
+	assert.NoError(t, err)
+	if assert.NotNil(t, o) {
+		assert.Equal(t, "0x1", o.Cycle.Edges[0].ID)
+		assert.True(t, o.Profit.Currency.Equal(USDC))
+		assert.Len(t, o.Curve, 1)
+	}
+
+	// within the fees
+	near := registry.Entry{ID: "0x3", Pool: testpools.FullRange(SUI, USDC, constants.FeeLow, 3, 1e12)}
+	o, err = SizeTwoPools(cheap, near, SUI, nil, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, o)
+
+	_, err = SizeTwoPools(cheap, registry.Entry{ID: "0x4", Pool: testpools.FullRange(SUI, USDT, constants.FeeLow, 0, 1e12)}, SUI, nil, 4)
+	assert.ErrorIs(t, err, ErrDifferentPairs)
+	_, err = SizeTwoPools(cheap, expensive, USDT, nil, 4)
+	assert.ErrorIs(t, err, ErrTokenNotInPools)
+	_, err = SizeTwoPools(cheap, expensive, SUI, nil, -1)
+	assert.ErrorIs(t, err, ErrInvalidCurveSize)
+}
@@ -15,6 +15,9 @@ const (
 	FeeMax uint64 = 1000000
 )
 
+// The denominator of a pool's protocol fee rate, i.e. the share of the swap fee taken by the protocol.
+const ProtocolFeeDenominator uint64 = 10000
+
 // The default factory tick spacings by fee amount.
 var TickSpacings = map[uint64]int{
 	FeeLowest: 1,
@@ -30,4 +33,6 @@ var (
 
 	// used in liquidity amount math
 	Q64 = new(big.Int).Exp(big.NewInt(2), big.NewInt(64), nil)
+	// the denominator of a squared Q64.64 sqrt price
+	Q128 = new(big.Int).Exp(big.NewInt(2), big.NewInt(128), nil)
 )
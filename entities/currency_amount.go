@@ -1,9 +1,14 @@
 package entities
 
 import (
+	"errors"
 	"math/big"
+)
 
-	"github.com/shopspring/decimal"
+var (
+	ErrAmountOverflow  = errors.New("currency amount exceeds maximum value (uint256)")
+	ErrNegativeAmount  = errors.New("currency amount is negative")
+	ErrZeroDenominator = errors.New("denominator must not be zero")
 )
 
 type CurrencyAmount struct {
@@ -31,19 +36,38 @@ func FromFractionalAmount(currency Currency, numerator *big.Int, denominator *bi
 	return newCurrencyAmount(currency, numerator, denominator)
 }
 
-// NewCurrencyAmount creates a new CurrencyAmount instance
-func newCurrencyAmount(currency Currency, numerator, denominator *big.Int) *CurrencyAmount {
+/**
+ * Construct a currency amount, returning an error instead of panicking if it exceeds uint256
+ * @param currency the currency
+ * @param numerator the numerator of the fractional token amount
+ * @param denominator the denominator of the fractional token amount
+ * @throws ErrZeroDenominator if the denominator is zero
+ * @throws ErrAmountOverflow if the amount exceeds uint256
+ */
+func NewCurrencyAmount(currency Currency, numerator, denominator *big.Int) (*CurrencyAmount, error) {
+	if denominator.Sign() == 0 {
+		return nil, ErrZeroDenominator
+	}
 	f := NewFraction(numerator, denominator)
 
 	if f.Quotient().Cmp(MaxUint256) > 0 {
-		panic("Currency amount exceeds maximum value(uint256)")
+		return nil, ErrAmountOverflow
 	}
 
 	return &CurrencyAmount{
 		Currency:     currency,
 		Fraction:     f,
 		DecimalScale: new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Decimals())), nil),
+	}, nil
+}
+
+// newCurrencyAmount is NewCurrencyAmount for the unchecked API, it panics where NewCurrencyAmount errors
+func newCurrencyAmount(currency Currency, numerator, denominator *big.Int) *CurrencyAmount {
+	ca, err := NewCurrencyAmount(currency, numerator, denominator)
+	if err != nil {
+		panic(err)
 	}
+	return ca
 }
 
 // Add adds two currency amounts together
@@ -58,6 +82,77 @@ func (ca *CurrencyAmount) Subtract(other *CurrencyAmount) *CurrencyAmount {
 	return FromFractionalAmount(ca.Currency, subtracted.Numerator, subtracted.Denominator)
 }
 
+/**
+ * Adds two amounts of the same currency
+ * @param other the amount to add
+ * @throws ErrDifferentCurrencies if the currencies differ
+ * @throws ErrNegativeAmount if the sum is negative
+ * @throws ErrAmountOverflow if the sum exceeds uint256
+ */
+func (ca *CurrencyAmount) AddChecked(other *CurrencyAmount) (*CurrencyAmount, error) {
+	if !ca.Currency.Equal(other.Currency) {
+		return nil, ErrDifferentCurrencies
+	}
+	return checked(ca.Currency, ca.Fraction.Add(other.Fraction))
+}
+
+/**
+ * Subtracts an amount of the same currency
+ * @param other the amount to subtract
+ * @throws ErrDifferentCurrencies if the currencies differ
+ * @throws ErrNegativeAmount if other is larger than the amount
+ * @throws ErrAmountOverflow if the difference exceeds uint256
+ */
+func (ca *CurrencyAmount) SubtractChecked(other *CurrencyAmount) (*CurrencyAmount, error) {
+	if !ca.Currency.Equal(other.Currency) {
+		return nil, ErrDifferentCurrencies
+	}
+	return checked(ca.Currency, ca.Fraction.Subtract(other.Fraction))
+}
+
+func checked(currency Currency, f *Fraction) (*CurrencyAmount, error) {
+	if f.Numerator.Sign()*f.Denominator.Sign() < 0 {
+		return nil, ErrNegativeAmount
+	}
+	return NewCurrencyAmount(currency, f.Numerator, f.Denominator)
+}
+
+/**
+ * Returns whether the amount is less than another amount of the same currency
+ * @param other the amount to compare
+ * @throws ErrDifferentCurrencies if the currencies differ
+ */
+func (ca *CurrencyAmount) LessThan(other *CurrencyAmount) (bool, error) {
+	if !ca.Currency.Equal(other.Currency) {
+		return false, ErrDifferentCurrencies
+	}
+	return ca.Fraction.LessThan(other.Fraction), nil
+}
+
+/**
+ * Returns whether the amount equals another amount of the same currency
+ * @param other the amount to compare
+ * @throws ErrDifferentCurrencies if the currencies differ
+ */
+func (ca *CurrencyAmount) EqualTo(other *CurrencyAmount) (bool, error) {
+	if !ca.Currency.Equal(other.Currency) {
+		return false, ErrDifferentCurrencies
+	}
+	return ca.Fraction.EqualTo(other.Fraction), nil
+}
+
+/**
+ * Returns whether the amount is greater than another amount of the same currency
+ * @param other the amount to compare
+ * @throws ErrDifferentCurrencies if the currencies differ
+ */
+func (ca *CurrencyAmount) GreaterThan(other *CurrencyAmount) (bool, error) {
+	if !ca.Currency.Equal(other.Currency) {
+		return false, ErrDifferentCurrencies
+	}
+	return ca.Fraction.GreaterThan(other.Fraction), nil
+}
+
 // Multiply multiplies two currency amounts
 func (ca *CurrencyAmount) Multiply(other *Fraction) *CurrencyAmount {
 	multiplied := ca.Fraction.Multiply(other)
@@ -83,9 +178,27 @@ func (ca *CurrencyAmount) ToFixed(decimalPlaces int32) string {
 	return ca.Fraction.Divide(NewFraction(ca.DecimalScale, big.NewInt(1))).ToFixed(decimalPlaces)
 }
 
-// ToExact returns the currency amount as a string with the specified number of digits after the decimal
+// ToExact returns the currency amount as a string with all the digits of its raw amount
 func (ca *CurrencyAmount) ToExact() string {
-	return decimal.NewFromBigInt(ca.Quotient(), 0).Div(decimal.NewFromBigInt(ca.DecimalScale, 0)).String()
+	return NewFraction(ca.Quotient(), ca.DecimalScale).FormatFixed(int32(ca.Currency.Decimals()), FormatOptions{TrimTrailingZeros: true})
+}
+
+/**
+ * Formats the currency amount exactly with the given number of digits after the decimal point
+ * @param decimalPlaces the number of digits after the decimal point
+ * @param opts the rounding and layout of the result
+ */
+func (ca *CurrencyAmount) FormatFixed(decimalPlaces int32, opts FormatOptions) string {
+	return ca.Fraction.Divide(NewFraction(ca.DecimalScale, big.NewInt(1))).FormatFixed(decimalPlaces, opts)
+}
+
+/**
+ * Formats the currency amount exactly with the given number of significant digits
+ * @param significantDigits the number of significant digits
+ * @param opts the rounding and layout of the result
+ */
+func (ca *CurrencyAmount) FormatSignificant(significantDigits int32, opts FormatOptions) string {
+	return ca.Fraction.Divide(NewFraction(ca.DecimalScale, big.NewInt(1))).FormatSignificant(significantDigits, opts)
 }
 
 func (ca *CurrencyAmount) Wrapped() *CurrencyAmount {
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidDecimal  = errors.New("invalid decimal amount")
+	ErrTooManyDecimals = errors.New("amount has more decimal places than the currency")
+)
+
+type parseOptions struct {
+	rounding *Rounding
+}
+
+// ParseOption configures ParseCurrencyAmount
+type ParseOption func(*parseOptions)
+
+// WithParseRounding rounds amounts with more decimal places than the currency instead of rejecting them
+func WithParseRounding(rounding Rounding) ParseOption {
+	return func(o *parseOptions) {
+		o.rounding = &rounding
+	}
+}
+
+/**
+ * Parses a human-readable decimal amount of a currency, e.g. "12.34" USDC is the raw amount 12340000. Only plain
+ * decimals are accepted: digits with an optional decimal point, no sign, exponent or separators.
+ * @param currency the currency of the amount
+ * @param s the decimal amount
+ * @param opts the options, e.g. WithParseRounding
+ * @throws ErrInvalidDecimal if s is not a plain decimal
+ * @throws ErrTooManyDecimals if s has more decimal places than the currency and no rounding is given
+ * @throws ErrAmountOverflow if the raw amount exceeds uint256
+ */
+func ParseCurrencyAmount(currency Currency, s string, opts ...ParseOption) (*CurrencyAmount, error) {
+	o := &parseOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, ErrInvalidDecimal
+	}
+
+	decimals := int(currency.Decimals())
+	raw, _ := new(big.Int).SetString(whole+fraction, 10)
+	if excess := len(fraction) - decimals; excess > 0 {
+		if o.rounding == nil && strings.TrimRight(fraction[decimals:], "0") != "" {
+			return nil, ErrTooManyDecimals
+		}
+		rounding := RoundDown
+		if o.rounding != nil {
+			rounding = *o.rounding
+		}
+		raw = divRound(raw, new(big.Int).Exp(tenInt, big.NewInt(int64(excess)), nil), rounding)
+	} else {
+		raw.Mul(raw, new(big.Int).Exp(tenInt, big.NewInt(int64(-excess)), nil))
+	}
+	return NewCurrencyAmount(currency, raw, big.NewInt(1))
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCurrencyAmount(t *testing.T) {
+	for s, want := range map[string]int64{
+		"12.34":     12340000,
+		"12.345678": 12345678,
+		"0.000001":  1,
+		".5":        500000,
+		"7.":        7000000,
+		"0":         0,
+		"007.10":    7100000,
+		"1.0000000": 1000000, // trailing zeros beyond the decimals are exact
+	} {
+		amount, err := ParseCurrencyAmount(USDC, s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, big.NewInt(want), amount.Quotient(), s)
+			assert.True(t, amount.Currency.Equal(USDC))
+		}
+	}
+
+	for _, s := range []string{"", ".", "1e6", "1E-2", "-1", "+1", "1,000", "1.2.3", " 1", "0x10", "NaN"} {
+		_, err := ParseCurrencyAmount(USDC, s)
+		assert.ErrorIs(t, err, ErrInvalidDecimal, s)
+	}
+
+	_, err := ParseCurrencyAmount(USDC, "1.0000005")
+	assert.ErrorIs(t, err, ErrTooManyDecimals)
+	_, err = ParseCurrencyAmount(USDC, "1"+MaxUint256.String())
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	for _, tt := range []struct {
+		s        string
+		rounding Rounding
+		want     int64
+	}{
+		{"1.0000005", RoundDown, 1000000},
+		{"1.0000005", RoundUp, 1000001},
+		{"1.0000005", RoundHalfUp, 1000001},
+		{"1.0000005", RoundHalfEven, 1000000},
+		{"1.0000015", RoundHalfEven, 1000002},
+		{"1.00000049", RoundHalfUp, 1000000},
+		{"1.00000051", RoundHalfEven, 1000001},
+		{"1.0000001", RoundUp, 1000001},
+		{"1.00000000001", RoundUp, 1000001},
+	} {
+		amount, err := ParseCurrencyAmount(USDC, tt.s, WithParseRounding(tt.rounding))
+		if assert.NoError(t, err) {
+			assert.Equal(t, big.NewInt(tt.want), amount.Quotient(), "%s rounded %d", tt.s, tt.rounding)
+		}
+	}
+}
+
+func TestDivRound(t *testing.T) {
+	for _, tt := range []struct {
+		numerator, denominator int64
+		want                   [4]int64 // down, half up, half even, up
+	}{
+		{7, 2, [4]int64{3, 4, 4, 4}},
+		{5, 2, [4]int64{2, 3, 2, 3}},
+		{-5, 2, [4]int64{-2, -3, -2, -3}},
+		{5, -2, [4]int64{-2, -3, -2, -3}},
+		{-7, 3, [4]int64{-2, -2, -2, -3}},
+		{8, 3, [4]int64{2, 3, 3, 3}},
+		{6, 3, [4]int64{2, 2, 2, 2}},
+	} {
+		for i, rounding := range []Rounding{RoundDown, RoundHalfUp, RoundHalfEven, RoundUp} {
+			got := divRound(big.NewInt(tt.numerator), big.NewInt(tt.denominator), rounding)
+			assert.Equal(t, big.NewInt(tt.want[i]), got, "%d/%d rounded %d", tt.numerator, tt.denominator, rounding)
+		}
+	}
+}
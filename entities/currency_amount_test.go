@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCurrencyAmount(t *testing.T) {
+	amount, err := NewCurrencyAmount(USDC, big.NewInt(3), big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), amount.Quotient())
+	assert.Equal(t, big.NewInt(1e6), amount.DecimalScale)
+
+	_, err = NewCurrencyAmount(USDC, new(big.Int).Add(MaxUint256, big.NewInt(1)), big.NewInt(1))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = NewCurrencyAmount(USDC, big.NewInt(1), big.NewInt(0))
+	assert.ErrorIs(t, err, ErrZeroDenominator)
+	assert.Panics(t, func() { FromRawAmount(USDC, new(big.Int).Add(MaxUint256, big.NewInt(1))) })
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	one := FromRawAmount(USDC, big.NewInt(1))
+	two := FromRawAmount(USDC, big.NewInt(2))
+
+	sum, err := one.AddChecked(two)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3), sum.Quotient())
+	difference, err := two.SubtractChecked(one)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), difference.Quotient())
+	difference, err = two.SubtractChecked(two)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, difference.Quotient().Sign())
+
+	_, err = one.SubtractChecked(two)
+	assert.ErrorIs(t, err, ErrNegativeAmount)
+	_, err = one.AddChecked(FromRawAmount(USDC, big.NewInt(-2)))
+	assert.ErrorIs(t, err, ErrNegativeAmount)
+	_, err = FromRawAmount(USDC, MaxUint256).AddChecked(one)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	dai := FromRawAmount(DAI, big.NewInt(1))
+	_, err = one.AddChecked(dai)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+	_, err = one.SubtractChecked(dai)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+}
+
+func TestCurrencyAmountComparisons(t *testing.T) {
+	one := FromRawAmount(USDC, big.NewInt(1))
+	half := FromFractionalAmount(USDC, big.NewInt(1), big.NewInt(2))
+
+	less, err := half.LessThan(one)
+	assert.NoError(t, err)
+	assert.True(t, less)
+	greater, err := half.GreaterThan(one)
+	assert.NoError(t, err)
+	assert.False(t, greater)
+	equal, err := one.EqualTo(FromFractionalAmount(USDC, big.NewInt(2), big.NewInt(2)))
+	assert.NoError(t, err)
+	assert.True(t, equal)
+
+	dai := FromRawAmount(DAI, big.NewInt(1))
+	_, err = one.LessThan(dai)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+	_, err = one.EqualTo(dai)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+	_, err = one.GreaterThan(dai)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"math/big"
+)
+
+// Depth is what it takes to move the price of a pool by a percentage in one direction
+type Depth struct {
+	Percent      *Percent        // how far the price of the input token falls
+	AmountIn     *CurrencyAmount // the input needed, including fees
+	AmountOut    *CurrencyAmount // the output received for it
+	SqrtRatioX64 *big.Int        // the sqrt price reached, clamped to the valid sqrt price range
+}
+
+// DepthLevel is the depth of a pool at ±Percent from the current price of token0 in token1
+type DepthLevel struct {
+	Percent    *Percent
+	ZeroForOne *Depth // selling token0, the price of token0 falls by Percent
+	OneForZero *Depth // selling token1, the price of token0 rises by Percent, i.e. that of token1 falls by Percent/(1+Percent)
+}
+
+/**
+ * Returns the input amount, including fees, that moves the price of the input token down by the given percentage,
+ * and the output amount received for it, walking the initialized ticks on the way
+ * @param tokenIn the token to sell
+ * @param percent how far the price of tokenIn falls, from 0 to 100%
+ */
+func (p *Pool) Depth(tokenIn *Token, percent *Percent) (*Depth, error) {
+	if !p.InvolvesToken(tokenIn) {
+		return nil, ErrTokenNotInvolved
+	}
+	limit, err := p.sqrtPriceForSlippage(tokenIn.Equal(p.Token0), percent)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.GetInputAmountToSqrtPrice(limit)
+	if err != nil {
+		return nil, err
+	}
+	return &Depth{
+		Percent:      percent,
+		AmountIn:     result.AmountIn,
+		AmountOut:    result.AmountOut,
+		SqrtRatioX64: result.SqrtRatioX64,
+	}, nil
+}
+
+/**
+ * Returns the depth of the pool at ±N% from the current price of token0 in token1 for several percentages at once,
+ * in the given order. The price falls to price * (1 - N) on one side and rises to price * (1 + N) on the other.
+ * @param percents the price moves to quote, each from 0 to 100%
+ */
+func (p *Pool) DepthTable(percents ...*Percent) ([]DepthLevel, error) {
+	levels := make([]DepthLevel, 0, len(percents))
+	for _, percent := range percents {
+		zeroForOne, err := p.Depth(p.Token0, percent)
+		if err != nil {
+			return nil, err
+		}
+		// the price of token0 rises by N when the price of token1 falls by N / (1 + N)
+		n := percent.Fraction.Reduce()
+		oneForZero, err := p.Depth(p.Token1, NewPercent(n.Numerator, new(big.Int).Add(n.Denominator, n.Numerator)))
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, DepthLevel{Percent: percent, ZeroForOne: zeroForOne, OneForZero: oneForZero})
+	}
+	return levels, nil
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepth(t *testing.T) {
+	pool := newTestPool()
+	twoPercent := NewPercent(big.NewInt(2), big.NewInt(100))
+
+	// L * (1/sqrt(0.98) - 1) / (1 - 0.05%) in and L * (1 - sqrt(0.98)) out
+	depth, err := pool.Depth(pool.Token0, twoPercent)
+	assert.NoError(t, err)
+	assert.Equal(t, "10157623363892697", depth.AmountIn.Quotient().String())
+	assert.Equal(t, "10050506338833465", depth.AmountOut.Quotient().String())
+	assert.Equal(t, pool.Token1, depth.AmountOut.Currency)
+
+	// swapping the quoted input moves the price by the percentage, up to the rounding of the last step
+	result, err := pool.GetOutputAmount(depth.AmountIn, nil)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, new(big.Int).Sub(result.AmountOut.Quotient(), depth.AmountOut.Quotient()).CmpAbs(big.NewInt(1)), 0)
+	moved := NewFraction(new(big.Int).Mul(result.SqrtRatioX64, result.SqrtRatioX64), new(big.Int).Mul(pool.SqrtRatioX64, pool.SqrtRatioX64))
+	assert.Equal(t, "0.980000", moved.ToFixed(6))
+
+	depth, err = pool.Depth(pool.Token0, NewPercent(big.NewInt(0), big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, depth.AmountIn.Quotient().Sign())
+
+	_, err = pool.Depth(WETH9[1], twoPercent)
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
+	_, err = pool.Depth(pool.Token0, NewPercent(big.NewInt(2), big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+}
+
+func TestDepthTable(t *testing.T) {
+	pool := newTestPool()
+	levels, err := pool.DepthTable(
+		NewPercent(big.NewInt(1), big.NewInt(100)),
+		NewPercent(big.NewInt(2), big.NewInt(100)),
+		NewPercent(big.NewInt(5), big.NewInt(100)),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, levels, 3)
+	for i, level := range levels {
+		assert.True(t, level.ZeroForOne.AmountIn.Currency.Equal(pool.Token0))
+		assert.True(t, level.OneForZero.AmountIn.Currency.Equal(pool.Token1))
+		if i > 0 {
+			assert.Equal(t, 1, level.ZeroForOne.AmountIn.Quotient().Cmp(levels[i-1].ZeroForOne.AmountIn.Quotient()))
+		}
+	}
+
+	// at a price of 1, +N% is a smaller move of the sqrt price than -N%, so it takes less input
+	assert.Equal(t, -1, levels[0].OneForZero.AmountIn.Quotient().Cmp(levels[0].ZeroForOne.AmountIn.Quotient()))
+
+	_, err = pool.DepthTable(NewPercent(big.NewInt(-1), big.NewInt(100)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+}
+
+func TestDepthTableAsymmetric(t *testing.T) {
+	// a price of about 10 with full range liquidity, and more liquidity just above the price
+	tickCurrent := 23030
+	ticks := []Tick{
+		{Index: NearestUsableTick(utils.MinTick, 10), LiquidityNet: OneEther, LiquidityGross: OneEther},
+		{Index: tickCurrent + 10, LiquidityNet: OneEther, LiquidityGross: OneEther},
+		{Index: tickCurrent + 100, LiquidityNet: new(big.Int).Neg(OneEther), LiquidityGross: OneEther},
+		{Index: NearestUsableTick(utils.MaxTick, 10), LiquidityNet: new(big.Int).Neg(OneEther), LiquidityGross: OneEther},
+	}
+	provider, err := NewTickListDataProvider(ticks, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqrtRatioX64, err := utils.GetSqrtRatioAtTick(tickCurrent + 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewPool(USDC, DAI, constants.FeeLow, 10, sqrtRatioX64, OneEther, tickCurrent+3, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels, err := pool.DepthTable(NewPercent(big.NewInt(2), big.NewInt(100)), NewPercent(big.NewInt(1), big.NewInt(2)))
+	assert.NoError(t, err)
+	for i, want := range [][2]string{{"0.980000", "1.020000"}, {"0.500000", "1.500000"}} {
+		// the reached prices of token0 relative to the current one
+		current := new(big.Int).Mul(pool.SqrtRatioX64, pool.SqrtRatioX64)
+		down, up := levels[i].ZeroForOne.SqrtRatioX64, levels[i].OneForZero.SqrtRatioX64
+		assert.Equal(t, want[0], NewFraction(new(big.Int).Mul(down, down), current).ToFixed(6))
+		assert.Equal(t, want[1], NewFraction(new(big.Int).Mul(up, up), current).ToFixed(6))
+	}
+}
@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"math/big"
+	"strings"
+)
+
+// FormatOptions configures the exact formatting of fractions
+type FormatOptions struct {
+	Rounding          Rounding
+	GroupSeparator    string // separates groups of three integer digits, e.g. ",", none if empty
+	TrimTrailingZeros bool   // drops trailing zeros after the decimal point, and the point if nothing is left
+}
+
+/**
+ * Formats the fraction exactly with the given number of digits after the decimal point. Negative decimal places round
+ * the integer part, e.g. to hundreds for -2.
+ * @param decimalPlaces the number of digits after the decimal point
+ * @param opts the rounding and layout of the result
+ */
+func (f *Fraction) FormatFixed(decimalPlaces int32, opts FormatOptions) string {
+	numerator, denominator := new(big.Int).Abs(f.Numerator), new(big.Int).Abs(f.Denominator)
+	negative := f.Numerator.Sign()*f.Denominator.Sign() < 0
+	return format(numerator, denominator, int(decimalPlaces), negative, opts)
+}
+
+/**
+ * Formats the fraction exactly with the given number of significant digits. Integer digits beyond them are zeros.
+ * @param significantDigits the number of significant digits, zero or less formats zero
+ * @param opts the rounding and layout of the result
+ */
+func (f *Fraction) FormatSignificant(significantDigits int32, opts FormatOptions) string {
+	numerator, denominator := new(big.Int).Abs(f.Numerator), new(big.Int).Abs(f.Denominator)
+	if significantDigits <= 0 || numerator.Sign() == 0 {
+		return format(big.NewInt(0), big.NewInt(1), 0, false, opts)
+	}
+	negative := f.Numerator.Sign()*f.Denominator.Sign() < 0
+
+	// find the decimal places that leave the significant digits before the point, i.e.
+	// 10^(digits-1) <= numerator * 10^places / denominator < 10^digits
+	digits := int(significantDigits)
+	places := digits - (len(numerator.String()) - len(denominator.String())) - 1
+	lower := pow10(digits - 1)
+	for {
+		scaled := scale(numerator, denominator, places)
+		if scaled.Cmp(lower) < 0 {
+			places++
+		} else if scaled.Cmp(new(big.Int).Mul(lower, tenInt)) >= 0 {
+			places--
+		} else {
+			break
+		}
+	}
+	// rounding up to the next power of ten adds a digit, e.g. 9.96 to 10.0 for 3 digits, which is dropped
+	if rounded := scaleRound(numerator, denominator, places, opts.Rounding); rounded.Cmp(new(big.Int).Mul(lower, tenInt)) == 0 {
+		places--
+	}
+	return format(numerator, denominator, places, negative, opts)
+}
+
+// format formats numerator / denominator, both not negative, with the given decimal places
+func format(numerator, denominator *big.Int, places int, negative bool, opts FormatOptions) string {
+	rounded := scaleRound(numerator, denominator, places, opts.Rounding)
+	digits := rounded.String()
+	var whole, fraction string
+	if places <= 0 {
+		if rounded.Sign() != 0 {
+			digits += strings.Repeat("0", -places)
+		}
+		whole = digits
+	} else {
+		if len(digits) <= places {
+			digits = strings.Repeat("0", places-len(digits)+1) + digits
+		}
+		whole, fraction = digits[:len(digits)-places], digits[len(digits)-places:]
+	}
+	if opts.TrimTrailingZeros {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+	if opts.GroupSeparator != "" {
+		whole = group(whole, opts.GroupSeparator)
+	}
+
+	var b strings.Builder
+	if negative && rounded.Sign() != 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(whole)
+	if fraction != "" {
+		b.WriteByte('.')
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// scale returns numerator * 10^places / denominator rounded down
+func scale(numerator, denominator *big.Int, places int) *big.Int {
+	return scaleRound(numerator, denominator, places, RoundDown)
+}
+
+func scaleRound(numerator, denominator *big.Int, places int, rounding Rounding) *big.Int {
+	if places >= 0 {
+		return divRound(new(big.Int).Mul(numerator, pow10(places)), denominator, rounding)
+	}
+	return divRound(numerator, new(big.Int).Mul(denominator, pow10(-places)), rounding)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(tenInt, big.NewInt(int64(n)), nil)
+}
+
+func group(digits, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
+	}
+	b.WriteString(digits[:first])
+	for i := first; i < len(digits); i += 3 {
+		b.WriteString(separator)
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func frac(numerator, denominator int64) *Fraction {
+	return NewFraction(big.NewInt(numerator), big.NewInt(denominator))
+}
+
+func TestFormatFixed(t *testing.T) {
+	assert.Equal(t, "1.00", frac(1, 1).ToFixed(2))
+	assert.Equal(t, "0.67", frac(2, 3).ToFixed(2))
+	assert.Equal(t, "-0.67", frac(-2, 3).ToFixed(2))
+	assert.Equal(t, "-0.67", frac(2, -3).ToFixed(2))
+	assert.Equal(t, "1300", frac(1250, 1).ToFixed(-2), "half up away from zero, to hundreds")
+	assert.Equal(t, "0", frac(-1, 3).ToFixed(0), "no negative zero")
+
+	for _, tt := range []struct {
+		f        *Fraction
+		rounding Rounding
+		want     string
+	}{
+		{frac(125, 100), RoundDown, "1.2"},
+		{frac(125, 100), RoundHalfUp, "1.3"},
+		{frac(125, 100), RoundHalfEven, "1.2"},
+		{frac(135, 100), RoundHalfEven, "1.4"},
+		{frac(121, 100), RoundUp, "1.3"},
+		{frac(-121, 100), RoundUp, "-1.3"},
+		{frac(-129, 100), RoundDown, "-1.2"},
+	} {
+		assert.Equal(t, tt.want, tt.f.FormatFixed(1, FormatOptions{Rounding: tt.rounding}))
+	}
+
+	options := FormatOptions{GroupSeparator: ",", TrimTrailingZeros: true}
+	assert.Equal(t, "1,234,567.5", frac(12345675, 10).FormatFixed(4, options))
+	assert.Equal(t, "-123,456", frac(-123456, 1).FormatFixed(2, options))
+	assert.Equal(t, "999", frac(999, 1).FormatFixed(2, options))
+	assert.Equal(t, "0", frac(0, 1).FormatFixed(2, options))
+
+	// Q64 values keep all their digits
+	q64 := NewFraction(new(big.Int).Add(constants.Q64, big.NewInt(1)), constants.Q64)
+	assert.Equal(t, "1.0000000000000000000542101086242752217", q64.FormatFixed(37, FormatOptions{}))
+	assert.Equal(t, "18446744073709551617", NewFraction(new(big.Int).Add(constants.Q64, big.NewInt(1)), big.NewInt(1)).ToFixed(0))
+}
+
+func TestFormatSignificant(t *testing.T) {
+	assert.Equal(t, "130", frac(125, 1).ToSignificant(2))
+	assert.Equal(t, "5.5", frac(545, 100).ToSignificant(2))
+	assert.Equal(t, "0.0005", frac(5, 10000).ToSignificant(5))
+	assert.Equal(t, "0.33333", frac(1, 3).ToSignificant(5))
+	assert.Equal(t, "10", frac(996, 100).ToSignificant(2))
+	assert.Equal(t, "-10", frac(-996, 100).ToSignificant(2))
+	assert.Equal(t, "0", frac(0, 1).ToSignificant(3))
+	assert.Equal(t, "0", frac(5, 1).ToSignificant(0))
+
+	assert.Equal(t, "10.0", frac(9996, 1000).FormatSignificant(3, FormatOptions{Rounding: RoundHalfUp}))
+	assert.Equal(t, "9.96", frac(996, 100).FormatSignificant(3, FormatOptions{}))
+	assert.Equal(t, "9.9", frac(996, 100).FormatSignificant(2, FormatOptions{Rounding: RoundDown}))
+	assert.Equal(t, "1.0", frac(1, 1).FormatSignificant(2, FormatOptions{}))
+	assert.Equal(t, "1,235,000", frac(1234567, 1).FormatSignificant(4, FormatOptions{Rounding: RoundUp, GroupSeparator: ","}))
+	assert.Equal(t, "0.0012", frac(125, 100000).FormatSignificant(2, FormatOptions{Rounding: RoundHalfEven}))
+
+	// beyond the 16 digits of the previous decimal division
+	third := NewFraction(big.NewInt(1), new(big.Int).Mul(constants.Q64, big.NewInt(3)))
+	assert.Equal(t, "0.000000000000000000018070036208091740567", third.ToSignificant(20))
+}
+
+func TestFormatCurrencyAmountsAndPrices(t *testing.T) {
+	weth, _ := new(big.Int).SetString("1234567890123456789012", 10)
+	amount := FromRawAmount(WETH9[1], weth)
+	assert.Equal(t, "1234.567890123456789012", amount.ToExact())
+	assert.Equal(t, "1,234.57", amount.FormatFixed(2, FormatOptions{Rounding: RoundHalfEven, GroupSeparator: ","}))
+	assert.Equal(t, "1234.5", amount.FormatSignificant(5, FormatOptions{Rounding: RoundDown}))
+	assert.Equal(t, "0.1", FromRawAmount(USDC, big.NewInt(100000)).ToExact())
+
+	price := NewPrice(USDC, DAI, big.NewInt(1e6), big.NewInt(15e17))
+	assert.Equal(t, "1.5", price.FormatSignificant(4, FormatOptions{TrimTrailingZeros: true}))
+	assert.Equal(t, "1.50", price.FormatFixed(2, FormatOptions{}))
+}
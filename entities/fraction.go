@@ -1,19 +1,30 @@
 package entities
 
 import (
-	"fmt"
-	"math"
+	"errors"
 	"math/big"
+	"regexp"
+)
 
-	"github.com/shopspring/decimal"
+var (
+	ErrInvalidFraction = errors.New("invalid fraction")
+	ErrDivideByZero    = errors.New("division by zero")
+)
+
+// decimalSyntax and ratioSyntax are the forms NewFractionFromString accepts. big.Rat alone also takes base prefixes
+// like "0x10" and underscores, and reads the parts of a ratio with a leading zero as octal, so those are left out.
+var (
+	decimalSyntax = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+	ratioSyntax   = regexp.MustCompile(`^[+-]?(0|[1-9]\d*)/[1-9]\d*$`)
 )
 
+// Fraction is a rational number. The results of its arithmetic are reduced and have a positive denominator.
 type Fraction struct {
 	Numerator   *big.Int
 	Denominator *big.Int
 }
 
-// NewFraction creates a new fraction
+// NewFraction creates a new fraction as given, a zero denominator panics when the fraction is used
 func NewFraction(numerator, denominator *big.Int) *Fraction {
 	return &Fraction{
 		Numerator:   numerator,
@@ -21,6 +32,70 @@ func NewFraction(numerator, denominator *big.Int) *Fraction {
 	}
 }
 
+/**
+ * Creates a new fraction, reduced and with a positive denominator
+ * @param numerator the numerator
+ * @param denominator the denominator
+ * @throws ErrZeroDenominator if the denominator is zero
+ */
+func NewFractionChecked(numerator, denominator *big.Int) (*Fraction, error) {
+	if denominator.Sign() == 0 {
+		return nil, ErrZeroDenominator
+	}
+	return NewFraction(numerator, denominator).Reduce(), nil
+}
+
+/**
+ * Parses a fraction from a decimal like "-12.5" or "1e-3", or a ratio like "1/3"
+ * @param s the string to parse
+ * @throws ErrInvalidFraction if s is neither, e.g. has a base prefix, underscores or a ratio part with a leading zero
+ */
+func NewFractionFromString(s string) (*Fraction, error) {
+	if !decimalSyntax.MatchString(s) && !ratioSyntax.MatchString(s) {
+		return nil, ErrInvalidFraction
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, ErrInvalidFraction
+	}
+	return NewFractionFromRat(r), nil
+}
+
+// NewFractionFromRat creates a fraction equal to r
+func NewFractionFromRat(r *big.Rat) *Fraction {
+	return NewFraction(new(big.Int).Set(r.Num()), new(big.Int).Set(r.Denom()))
+}
+
+// Rat returns the fraction as a big.Rat
+func (f *Fraction) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(f.Numerator, f.Denominator)
+}
+
+// Reduce returns the fraction divided by the greatest common divisor of its numerator and denominator, with a
+// positive denominator
+func (f *Fraction) Reduce() *Fraction {
+	gcd := new(big.Int).GCD(nil, nil, new(big.Int).Abs(f.Numerator), new(big.Int).Abs(f.Denominator))
+	if f.Numerator.Sign() == 0 {
+		gcd.Abs(f.Denominator)
+	}
+	if gcd.Sign() == 0 {
+		return NewFraction(new(big.Int).Set(f.Numerator), new(big.Int).Set(f.Denominator))
+	}
+	if f.Denominator.Sign() < 0 {
+		gcd.Neg(gcd)
+	}
+	return NewFraction(new(big.Int).Quo(f.Numerator, gcd), new(big.Int).Quo(f.Denominator, gcd))
+}
+
+// Cmp compares the fraction with the other fraction, returning -1, 0 or 1 if it is less than, equal to or greater
+func (f *Fraction) Cmp(other *Fraction) int {
+	c := new(big.Int).Mul(f.Numerator, other.Denominator).Cmp(new(big.Int).Mul(other.Numerator, f.Denominator))
+	if f.Denominator.Sign()*other.Denominator.Sign() < 0 {
+		return -c
+	}
+	return c
+}
+
 // Quotient performs floor division
 func (f *Fraction) Quotient() *big.Int {
 	return new(big.Int).Div(f.Numerator, f.Denominator)
@@ -39,95 +114,69 @@ func (f *Fraction) Invert() *Fraction {
 // Add adds two fractions
 func (f *Fraction) Add(other *Fraction) *Fraction {
 	if f.Denominator.Cmp(other.Denominator) == 0 {
-		return NewFraction(new(big.Int).Add(f.Numerator, other.Numerator), f.Denominator)
+		return NewFraction(new(big.Int).Add(f.Numerator, other.Numerator), f.Denominator).Reduce()
 	}
 	return NewFraction(
 		new(big.Int).Add(new(big.Int).Mul(f.Numerator, other.Denominator), new(big.Int).Mul(other.Numerator, f.Denominator)),
-		new(big.Int).Mul(f.Denominator, other.Denominator))
+		new(big.Int).Mul(f.Denominator, other.Denominator)).Reduce()
 }
 
 // Subtract subtracts two fractions
 func (f *Fraction) Subtract(other *Fraction) *Fraction {
 	if f.Denominator.Cmp(other.Denominator) == 0 {
-		return NewFraction(new(big.Int).Sub(f.Numerator, other.Numerator), f.Denominator)
+		return NewFraction(new(big.Int).Sub(f.Numerator, other.Numerator), f.Denominator).Reduce()
 	}
 	return NewFraction(
 		new(big.Int).Sub(new(big.Int).Mul(f.Numerator, other.Denominator), new(big.Int).Mul(other.Numerator, f.Denominator)),
-		new(big.Int).Mul(f.Denominator, other.Denominator))
+		new(big.Int).Mul(f.Denominator, other.Denominator)).Reduce()
 }
 
 // LessThan returns true if the fraction is less than the other fraction
 func (f *Fraction) LessThan(other *Fraction) bool {
-	return new(big.Int).Mul(f.Numerator, other.Denominator).Cmp(new(big.Int).Mul(other.Numerator, f.Denominator)) < 0
+	return f.Cmp(other) < 0
 }
 
 // EqualTo returns true if the fraction is equal to the other fraction
 func (f *Fraction) EqualTo(other *Fraction) bool {
-	return new(big.Int).Mul(f.Numerator, other.Denominator).Cmp(new(big.Int).Mul(other.Numerator, f.Denominator)) == 0
+	return f.Cmp(other) == 0
 }
 
 // GreaterThan returns true if the fraction is greater than the other fraction
 func (f *Fraction) GreaterThan(other *Fraction) bool {
-	return new(big.Int).Mul(f.Numerator, other.Denominator).Cmp(new(big.Int).Mul(other.Numerator, f.Denominator)) > 0
+	return f.Cmp(other) > 0
 }
 
 // Multiply multiplies two fractions
 func (f *Fraction) Multiply(other *Fraction) *Fraction {
-	return NewFraction(new(big.Int).Mul(f.Numerator, other.Numerator), new(big.Int).Mul(f.Denominator, other.Denominator))
+	return NewFraction(new(big.Int).Mul(f.Numerator, other.Numerator), new(big.Int).Mul(f.Denominator, other.Denominator)).Reduce()
 }
 
-// Divide divides two fractions
+// Divide divides two fractions, a zero divisor gives a zero denominator, see DivideChecked
 func (f *Fraction) Divide(other *Fraction) *Fraction {
-	return NewFraction(new(big.Int).Mul(f.Numerator, other.Denominator), new(big.Int).Mul(f.Denominator, other.Numerator))
+	return NewFraction(new(big.Int).Mul(f.Numerator, other.Denominator), new(big.Int).Mul(f.Denominator, other.Numerator)).Reduce()
+}
+
+/**
+ * Divides two fractions
+ * @param other the divisor
+ * @throws ErrDivideByZero if the divisor is zero
+ */
+func (f *Fraction) DivideChecked(other *Fraction) (*Fraction, error) {
+	if other.Numerator.Sign() == 0 {
+		return nil, ErrDivideByZero
+	}
+	return f.Divide(other), nil
 }
 
-// ToSignificant returns a significant string representation of the fraction
+// ToSignificant returns a significant string representation of the fraction, rounded half up, without trailing zeros
 // Example: NewFraction(big.NewInt(125), big.NewInt(1)).ToSignificant(2) // output: "130"
 func (f *Fraction) ToSignificant(significantDigits int32) string {
-	return roundToSignificantFigures(f, significantDigits).String()
-
+	return f.FormatSignificant(significantDigits, FormatOptions{Rounding: RoundHalfUp, TrimTrailingZeros: true})
 }
 
-// ToFixed returns a fixed string representation of the fraction
+// ToFixed returns a fixed string representation of the fraction, rounded half up
 func (f *Fraction) ToFixed(decimalPlaces int32) string {
-	return decimal.NewFromBigInt(f.Numerator, 0).Div(decimal.NewFromBigInt(f.Denominator, 0)).StringFixed(decimalPlaces)
+	return f.FormatFixed(decimalPlaces, FormatOptions{Rounding: RoundHalfUp})
 }
 
-var (
-	oneInt = big.NewInt(1)
-	twoInt = big.NewInt(2)
-	tenInt = big.NewInt(10)
-)
-
-// roundToSignificantFigures returns a copy of a decimal rounded to specified number of significant figures.
-// For negative or zero figures the function returns zero.
-// Result is normalized without trailing zeros.
-//
-// Example:
-//
-//	5.45 figures:2 --> 5.51
-//	545 figures:2 --> 550
-//
-// Ref: https://github.com/shopspring/decimal/pull/117/files#diff-84512ce9971597d4817ea830422f80d41b8f8f050b5998bd49499d8d1eebb16dR922
-func roundToSignificantFigures(f *Fraction, figures int32) decimal.Decimal {
-	if figures <= 0 {
-		return decimal.Zero
-	}
-	d := decimal.NewFromBigInt(f.Numerator, 0).Div(decimal.NewFromBigInt(f.Denominator, 0))
-	twoMant := d.Mul(decimal.NewFromFloat(math.Pow10(decimal.DivisionPrecision))).BigInt()
-	twoMant.Abs(twoMant)
-	twoMant.Mul(twoMant, twoInt)
-	upper := big.NewInt(int64(figures))
-	upper.Exp(tenInt, upper, nil)
-	upper.Mul(upper, twoInt)
-	upper.Sub(upper, oneInt)
-	m := int64(0)
-	for twoMant.Cmp(upper) >= 0 {
-		upper.Mul(upper, tenInt)
-		m++
-	}
-	if int64(d.Exponent())+m > int64(math.MaxInt32) {
-		panic(fmt.Sprintf("exponent %d overflows an int32", int64(d.Exponent())+m))
-	}
-	return d.Round(-d.Exponent() - int32(m))
-}
+var tenInt = big.NewInt(10)
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReduce(t *testing.T) {
+	for _, tt := range []struct {
+		f    *Fraction
+		want *Fraction
+	}{
+		{frac(6, 4), frac(3, 2)},
+		{frac(-6, 4), frac(-3, 2)},
+		{frac(6, -4), frac(-3, 2)},
+		{frac(-6, -4), frac(3, 2)},
+		{frac(0, -7), frac(0, 1)},
+		{frac(5, 1), frac(5, 1)},
+		{frac(0, 0), frac(0, 0)},
+	} {
+		assert.Equal(t, tt.want, tt.f.Reduce())
+	}
+
+	// arithmetic reduces, so chained operations stay small
+	f := frac(1, 1)
+	for i := 0; i < 100; i++ {
+		f = f.Multiply(frac(3, 7)).Divide(frac(6, 14))
+	}
+	assert.Equal(t, frac(1, 1), f)
+	assert.Equal(t, frac(1, 2), frac(1, 6).Add(frac(1, 3)))
+	assert.Equal(t, frac(-1, 6), frac(1, 6).Subtract(frac(1, 3)))
+	assert.Equal(t, frac(1, 2), frac(1, 4).Add(frac(1, 4)))
+	assert.Equal(t, frac(-2, 3), frac(2, 3).Divide(frac(-1, 1)))
+}
+
+func TestCmp(t *testing.T) {
+	assert.Equal(t, -1, frac(1, 3).Cmp(frac(1, 2)))
+	assert.Equal(t, 0, frac(2, 4).Cmp(frac(1, 2)))
+	assert.Equal(t, 1, frac(2, 3).Cmp(frac(1, 2)))
+	// signs in the denominator
+	assert.Equal(t, -1, frac(1, -3).Cmp(frac(1, 2)))
+	assert.Equal(t, 1, frac(1, 2).Cmp(frac(1, -3)))
+	assert.Equal(t, 0, frac(-1, -2).Cmp(frac(1, 2)))
+	assert.True(t, frac(1, -3).LessThan(frac(0, 1)))
+	assert.True(t, frac(-1, -3).GreaterThan(frac(0, 1)))
+	assert.True(t, frac(-2, -4).EqualTo(frac(1, 2)))
+}
+
+func TestFractionConstructors(t *testing.T) {
+	f, err := NewFractionChecked(big.NewInt(10), big.NewInt(-4))
+	assert.NoError(t, err)
+	assert.Equal(t, frac(-5, 2), f)
+	_, err = NewFractionChecked(big.NewInt(1), big.NewInt(0))
+	assert.ErrorIs(t, err, ErrZeroDenominator)
+
+	for s, want := range map[string]*Fraction{
+		"12.5":  frac(25, 2),
+		"-0.25": frac(-1, 4),
+		"1e-3":  frac(1, 1000),
+		"1/3":   frac(1, 3),
+		"4/6":   frac(2, 3),
+		"7":     frac(7, 1),
+		"+.5":   frac(1, 2),
+		"-2/8":  frac(-1, 4),
+		"0/3":   frac(0, 1),
+		"010":   frac(10, 1),
+	} {
+		f, err := NewFractionFromString(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, want, f, s)
+		}
+	}
+	for _, s := range []string{"", "abc", "1/0", "1..2", "0x10", "0b101", "0o7", "1_000", "1/-3", "010/3", "1/010", "1.5/2", " 1", "Inf"} {
+		_, err := NewFractionFromString(s)
+		assert.ErrorIs(t, err, ErrInvalidFraction, s)
+	}
+
+	q, err := frac(1, 2).DivideChecked(frac(-3, 4))
+	assert.NoError(t, err)
+	assert.Equal(t, frac(-2, 3), q)
+	_, err = frac(1, 2).DivideChecked(frac(0, 5))
+	assert.ErrorIs(t, err, ErrDivideByZero)
+
+	r := big.NewRat(-3, 9)
+	assert.Equal(t, frac(-1, 3), NewFractionFromRat(r))
+	assert.Equal(t, 0, frac(2, -6).Rat().Cmp(r))
+}
@@ -0,0 +1,196 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrInvalidJSONNumber   = errors.New("invalid big integer, expected a decimal string")
+	ErrUnsupportedCurrency = errors.New("only token currencies can be encoded")
+	ErrMissingJSONCurrency = errors.New("missing currency")
+)
+
+// Big integers are encoded as decimal strings, since JSON numbers lose precision above 2^53 in JavaScript. Field names
+// are snake_case like the fields of Sui events, in every JSON format of the SDK.
+
+func parseBigInt(field, s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %q", ErrInvalidJSONNumber, field, s)
+	}
+	return n, nil
+}
+
+type tokenJSON struct {
+	ChainID  uint   `json:"chain_id"`
+	Address  string `json:"address"`
+	Decimals uint   `json:"decimals"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+}
+
+func (t *Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tokenJSON{
+		ChainID:  t.chainId,
+		Address:  t.Address,
+		Decimals: t.decimals,
+		Symbol:   t.symbol,
+		Name:     t.name,
+	})
+}
+
+func (t *Token) UnmarshalJSON(data []byte) error {
+	var v tokenJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Decimals >= 255 {
+		return fmt.Errorf("token %s: decimals must be less than 255", v.Address)
+	}
+	*t = *NewToken(v.ChainID, v.Address, v.Decimals, v.Symbol, v.Name)
+	// NewToken points the base currency at the token it returned, point it at this one instead
+	t.baseCurrency.currency = t
+	return nil
+}
+
+type tickJSON struct {
+	Index          int    `json:"index"`
+	LiquidityGross string `json:"liquidity_gross"`
+	LiquidityNet   string `json:"liquidity_net"`
+}
+
+func (t Tick) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tickJSON{
+		Index:          t.Index,
+		LiquidityGross: t.LiquidityGross.String(),
+		LiquidityNet:   t.LiquidityNet.String(),
+	})
+}
+
+func (t *Tick) UnmarshalJSON(data []byte) error {
+	var v tickJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	liquidityGross, err := parseBigInt("liquidity_gross", v.LiquidityGross)
+	if err != nil {
+		return err
+	}
+	liquidityNet, err := parseBigInt("liquidity_net", v.LiquidityNet)
+	if err != nil {
+		return err
+	}
+	*t = Tick{Index: v.Index, LiquidityGross: liquidityGross, LiquidityNet: liquidityNet}
+	return nil
+}
+
+type currencyAmountJSON struct {
+	Currency    *Token `json:"currency"`
+	Numerator   string `json:"numerator"`
+	Denominator string `json:"denominator"`
+}
+
+func (ca *CurrencyAmount) MarshalJSON() ([]byte, error) {
+	if !ca.Currency.IsToken() {
+		return nil, ErrUnsupportedCurrency
+	}
+	return json.Marshal(currencyAmountJSON{
+		Currency:    ca.Currency.Wrapped(),
+		Numerator:   ca.Numerator.String(),
+		Denominator: ca.Denominator.String(),
+	})
+}
+
+func (ca *CurrencyAmount) UnmarshalJSON(data []byte) error {
+	var v currencyAmountJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Currency == nil {
+		return ErrMissingJSONCurrency
+	}
+	numerator, err := parseBigInt("numerator", v.Numerator)
+	if err != nil {
+		return err
+	}
+	denominator, err := parseBigInt("denominator", v.Denominator)
+	if err != nil {
+		return err
+	}
+	if denominator.Sign() == 0 {
+		return fmt.Errorf("%w: denominator is zero", ErrInvalidJSONNumber)
+	}
+	if new(big.Int).Div(numerator, denominator).Cmp(MaxUint256) > 0 {
+		return fmt.Errorf("%w: amount exceeds maximum value(uint256)", ErrInvalidJSONNumber)
+	}
+	*ca = *FromFractionalAmount(v.Currency, numerator, denominator)
+	return nil
+}
+
+type poolJSON struct {
+	Token0          *Token `json:"token0"`
+	Token1          *Token `json:"token1"`
+	Fee             uint64 `json:"fee"`
+	TickSpacing     int    `json:"tick_spacing"`
+	SqrtRatioX64    string `json:"sqrt_price_x64"`
+	Liquidity       string `json:"liquidity"`
+	TickCurrent     int    `json:"tick_current"`
+	ProtocolFeeRate uint64 `json:"protocol_fee_rate"`
+	Ticks           []Tick `json:"ticks"`
+}
+
+// MarshalJSON encodes the pool including its ticks, which requires a TickLister tick data provider. The sqrt ratio
+// lookup table is not encoded.
+func (p *Pool) MarshalJSON() ([]byte, error) {
+	lister, ok := p.TickDataProvider.(TickLister)
+	if !ok {
+		return nil, ErrTicksNotListable
+	}
+	return json.Marshal(poolJSON{
+		Token0:          p.Token0,
+		Token1:          p.Token1,
+		Fee:             p.Fee,
+		TickSpacing:     p.TickSpacing,
+		SqrtRatioX64:    p.SqrtRatioX64.String(),
+		Liquidity:       p.Liquidity.String(),
+		TickCurrent:     p.TickCurrent,
+		ProtocolFeeRate: p.ProtocolFeeRate,
+		Ticks:           lister.Ticks(),
+	})
+}
+
+// UnmarshalJSON decodes a pool encoded by MarshalJSON, validating it like NewPool and backing it with a TickListDataProvider
+func (p *Pool) UnmarshalJSON(data []byte) error {
+	var v poolJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Token0 == nil || v.Token1 == nil {
+		return ErrMissingJSONCurrency
+	}
+	sqrtRatioX64, err := parseBigInt("sqrt_price_x64", v.SqrtRatioX64)
+	if err != nil {
+		return err
+	}
+	liquidity, err := parseBigInt("liquidity", v.Liquidity)
+	if err != nil {
+		return err
+	}
+	if v.Ticks == nil {
+		v.Ticks = []Tick{}
+	}
+	ticks, err := NewTickListDataProvider(v.Ticks, v.TickSpacing)
+	if err != nil {
+		return err
+	}
+	pool, err := NewPool(v.Token0, v.Token1, v.Fee, v.TickSpacing, sqrtRatioX64, liquidity, v.TickCurrent, ticks)
+	if err != nil {
+		return err
+	}
+	pool.ProtocolFeeRate = v.ProtocolFeeRate
+	*p = *pool
+	return nil
+}
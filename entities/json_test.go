@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenJSON(t *testing.T) {
+	data, err := json.Marshal(USDC)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"chain_id":1,"address":"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48","decimals":6,"symbol":"USDC","name":"USD Coin"}`, string(data))
+
+	var token Token
+	assert.NoError(t, json.Unmarshal(data, &token))
+	assert.True(t, token.Equal(USDC))
+	assert.Equal(t, uint(6), token.Decimals())
+	assert.Equal(t, "USD Coin", token.Name())
+}
+
+func TestCurrencyAmountJSON(t *testing.T) {
+	amount := FromFractionalAmount(DAI, new(big.Int).Lsh(big.NewInt(1), 100), big.NewInt(3))
+	data, err := json.Marshal(amount)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"numerator":"1267650600228229401496703205376"`, "big integers are encoded as strings")
+
+	var decoded CurrencyAmount
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.Currency.Equal(DAI))
+	assert.Equal(t, amount.Numerator, decoded.Numerator)
+	assert.Equal(t, amount.Denominator, decoded.Denominator)
+	assert.Equal(t, amount.DecimalScale, decoded.DecimalScale)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"currency":{"address":"0x1"},"numerator":"1.5","denominator":"1"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"currency":{"address":"0x1"},"numerator":"1","denominator":"0"}`), &decoded))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"numerator":"1","denominator":"1"}`), &decoded), ErrMissingJSONCurrency)
+}
+
+func TestPoolJSON(t *testing.T) {
+	pool := newLayeredTestPool()
+	pool.ProtocolFeeRate = 2000
+
+	data, err := json.Marshal(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(data), `"sqrt_price_x64":"18446744073709551616"`)
+	assert.Contains(t, string(data), `{"index":-200,"liquidity_gross":"2000000000000000000","liquidity_net":"2000000000000000000"}`)
+
+	var decoded Pool
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, decoded.Token0.Equal(pool.Token0))
+	assert.True(t, decoded.Token1.Equal(pool.Token1))
+	assert.Equal(t, pool.Fee, decoded.Fee)
+	assert.Equal(t, pool.TickSpacing, decoded.TickSpacing)
+	assert.Equal(t, pool.SqrtRatioX64, decoded.SqrtRatioX64)
+	assert.Equal(t, pool.Liquidity, decoded.Liquidity)
+	assert.Equal(t, pool.TickCurrent, decoded.TickCurrent)
+	assert.Equal(t, pool.ProtocolFeeRate, decoded.ProtocolFeeRate)
+	assert.Equal(t, pool.TickDataProvider.(TickLister).Ticks(), decoded.TickDataProvider.(TickLister).Ticks())
+
+	// the decoded pool quotes like the original
+	want, _ := pool.GetOutputAmount(FromRawAmount(USDC, OneEther), nil)
+	got, err := decoded.GetOutputAmount(FromRawAmount(USDC, OneEther), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, want.AmountOut.Quotient(), got.AmountOut.Quotient())
+
+	pool.TickDataProvider = nil
+	_, err = json.Marshal(pool)
+	assert.ErrorIs(t, err, ErrTicksNotListable)
+
+	// decoded pools are validated
+	invalid := []byte(`{"token0":{"address":"0x1"},"token1":{"address":"0x2"},"fee":500,"tick_spacing":10,"sqrt_price_x64":"18446744073709551616","liquidity":"0","tick_current":5,"ticks":[]}`)
+	assert.ErrorIs(t, json.Unmarshal(invalid, &decoded), ErrInvalidSqrtRatioX64)
+}
@@ -0,0 +1,25 @@
+package entities
+
+import "math/big"
+
+var oneHundred = NewFraction(big.NewInt(100), big.NewInt(1))
+
+// Percent is a fraction formatted as a percentage, e.g. 1/200 is 0.5%
+type Percent struct {
+	*Fraction
+}
+
+// NewPercent creates a new percent of numerator over denominator, e.g. 1 over 200 for 0.5%
+func NewPercent(numerator, denominator *big.Int) *Percent {
+	return &Percent{Fraction: NewFraction(numerator, denominator)}
+}
+
+// ToSignificant returns the percentage, without a percent sign, with the most significant digits
+func (p *Percent) ToSignificant(significantDigits int32) string {
+	return p.Fraction.Multiply(oneHundred).ToSignificant(significantDigits)
+}
+
+// ToFixed returns the percentage, without a percent sign, with the specified number of digits after the decimal
+func (p *Percent) ToFixed(decimalPlaces int32) string {
+	return p.Fraction.Multiply(oneHundred).ToFixed(decimalPlaces)
+}
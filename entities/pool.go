@@ -16,14 +16,16 @@ var (
 	ErrSqrtPriceLimitX64TooHigh = errors.New("SqrtPriceLimitX64 too high")
 )
 
+// StepComputations describes a single step of a swap, i.e. the move towards the next initialized tick, word boundary or price limit
 type StepComputations struct {
-	sqrtPriceStartX64 *big.Int
-	tickNext          int
-	initialized       bool
-	sqrtPriceNextX64  *big.Int
-	amountIn          *big.Int
-	amountOut         *big.Int
-	feeAmount         *big.Int
+	SqrtPriceStartX64 *big.Int // the price at the beginning of the step
+	TickNext          int      // the next tick to swap to from the current tick in the swap direction
+	Initialized       bool     // whether TickNext is initialized or not
+	SqrtPriceNextX64  *big.Int // sqrt(price) for the next tick (1/0)
+	Liquidity         *big.Int // the in range liquidity the step was executed against
+	AmountIn          *big.Int // how much is being swapped in in this step, excluding fees
+	AmountOut         *big.Int // how much is being swapped out
+	FeeAmount         *big.Int // how much fee is being paid in
 }
 
 // Represents a V3 pool
@@ -36,6 +38,14 @@ type Pool struct {
 	Liquidity        *big.Int
 	TickCurrent      int
 	TickDataProvider TickDataProvider
+
+	// ProtocolFeeRate is the share of the swap fee taken by the protocol, denominated in constants.ProtocolFeeDenominator.
+	// It only affects the reported protocol fee, never the swap amounts.
+	ProtocolFeeRate uint64
+
+	// SqrtRatioTable is an optional lookup table used in place of utils.GetSqrtRatioAtTick while swapping,
+	// e.g. utils.SqrtRatioTableFor(pool.TickSpacing). A nil table computes every sqrt ratio.
+	SqrtRatioTable *utils.SqrtRatioTable
 }
 
 /**
@@ -91,69 +101,176 @@ func (p *Pool) InvolvesToken(token *Token) bool {
  * Given an input amount of a token, return the computed output amount, and a pool with state updated after the trade
  * @param inputAmount The input amount for which to quote the output amount
  * @param sqrtPriceLimitX64 The Q64.64 sqrt price limit
- * @returns The output amount and the pool with updated state
+ * @param opts Options such as WithTrace or WithRequireFullFill
+ * @returns The swap result, including the output amount and the pool with updated state
  */
-func (p *Pool) GetOutputAmount(inputAmount *CurrencyAmount, sqrtPriceLimitX64 *big.Int) (*CurrencyAmount, *Pool, int, error) {
+func (p *Pool) GetOutputAmount(inputAmount *CurrencyAmount, sqrtPriceLimitX64 *big.Int, opts ...SwapOption) (*SwapResult, error) {
 	if !(inputAmount.Currency.IsToken() && p.InvolvesToken(inputAmount.Currency.Wrapped())) {
-		return nil, nil, 0, ErrTokenNotInvolved
+		return nil, ErrTokenNotInvolved
 	}
 	zeroForOne := inputAmount.Currency.Equal(p.Token0)
-	outputAmount, sqrtRatioX64, liquidity, tickCurrent, numCrossTick, err := p.swap(zeroForOne, inputAmount.Quotient(), sqrtPriceLimitX64)
+	o := newSwapOptions(opts)
+	state, err := p.swap(zeroForOne, inputAmount.Quotient(), sqrtPriceLimitX64, o)
 	if err != nil {
-		return nil, nil, 0, err
-	}
-	var outputToken *Token
-	if zeroForOne {
-		outputToken = p.Token1
-	} else {
-		outputToken = p.Token0
-	}
-	pool, err := NewPool(p.Token0, p.Token1, p.Fee, p.TickSpacing, sqrtRatioX64, liquidity, tickCurrent, p.TickDataProvider)
-	if err != nil {
-		return nil, nil, 0, err
+		return nil, err
 	}
-	return FromRawAmount(outputToken, new(big.Int).Mul(outputAmount, constants.NegativeOne)), pool, numCrossTick, nil
+	return p.newSwapResult(zeroForOne, true, state, o)
 }
 
 /**
  * Given a desired output amount of a token, return the computed input amount and a pool with state updated after the trade
  * @param outputAmount the output amount for which to quote the input amount
  * @param sqrtPriceLimitX64 The Q64.64 sqrt price limit. If zero for one, the price cannot be less than this value after the swap. If one for zero, the price cannot be greater than this value after the swap
- * @returns The input amount and the pool with updated state
+ * @param opts Options such as WithTrace or WithRequireFullFill
+ * @returns The swap result, including the input amount and the pool with updated state
  */
-func (p *Pool) GetInputAmount(outputAmount *CurrencyAmount, sqrtPriceLimitX64 *big.Int) (*CurrencyAmount, *Pool, error) {
+func (p *Pool) GetInputAmount(outputAmount *CurrencyAmount, sqrtPriceLimitX64 *big.Int, opts ...SwapOption) (*SwapResult, error) {
 	if !(outputAmount.Currency.IsToken() && p.InvolvesToken(outputAmount.Currency.Wrapped())) {
-		return nil, nil, ErrTokenNotInvolved
+		return nil, ErrTokenNotInvolved
 	}
 	zeroForOne := outputAmount.Currency.Equal(p.Token1)
-	inputAmount, sqrtRatioX64, liquidity, tickCurrent, _, err := p.swap(zeroForOne, new(big.Int).Mul(outputAmount.Quotient(), constants.NegativeOne), sqrtPriceLimitX64)
+	o := newSwapOptions(opts)
+	state, err := p.swap(zeroForOne, new(big.Int).Mul(outputAmount.Quotient(), constants.NegativeOne), sqrtPriceLimitX64, o)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	var inputToken *Token
-	if zeroForOne {
-		inputToken = p.Token0
-	} else {
-		inputToken = p.Token1
+	return p.newSwapResult(zeroForOne, false, state, o)
+}
+
+/**
+ * Returns the current mid price of the pool in terms of token0, i.e. the ratio of token1 over token0
+ */
+func (p *Pool) Token0Price() *Price {
+	return NewPrice(p.Token0, p.Token1, constants.Q128, new(big.Int).Mul(p.SqrtRatioX64, p.SqrtRatioX64))
+}
+
+/**
+ * Returns the current mid price of the pool in terms of token1, i.e. the ratio of token0 over token1
+ */
+func (p *Pool) Token1Price() *Price {
+	return NewPrice(p.Token1, p.Token0, new(big.Int).Mul(p.SqrtRatioX64, p.SqrtRatioX64), constants.Q128)
+}
+
+/**
+ * Return the price of the given token in terms of the other token in the pool
+ * @param token The token to return price of
+ */
+func (p *Pool) PriceOf(token *Token) (*Price, error) {
+	if !p.InvolvesToken(token) {
+		return nil, ErrTokenNotInvolved
 	}
-	pool, err := NewPool(p.Token0, p.Token1, p.Fee, p.TickSpacing, sqrtRatioX64, liquidity, tickCurrent, p.TickDataProvider)
+	if p.Token0.Equal(token) {
+		return p.Token0Price(), nil
+	}
+	return p.Token1Price(), nil
+}
+
+/**
+ * Given a target sqrt price, return the input amount, including fees, needed to move the pool to exactly that price,
+ * walking the initialized ticks on the way. The input token follows from the direction of the move. Swapping the
+ * quoted input with GetOutputAmount reaches the target up to the rounding of the last step's sqrt price.
+ * @param targetSqrtPriceX64 The Q64.64 sqrt price to move the pool to
+ * @param opts Options such as WithTrace
+ * @returns The swap result, including the input amount and the pool with updated state
+ * @throws ErrPriceLimitAndTolerance if a slippage tolerance is given, the target is the limit
+ */
+func (p *Pool) GetInputAmountToSqrtPrice(targetSqrtPriceX64 *big.Int, opts ...SwapOption) (*SwapResult, error) {
+	o := newSwapOptions(opts)
+	if o.slippageTolerance != nil {
+		return nil, ErrPriceLimitAndTolerance
+	}
+	zeroForOne := targetSqrtPriceX64.Cmp(p.SqrtRatioX64) < 0
+	if targetSqrtPriceX64.Cmp(p.SqrtRatioX64) == 0 {
+		// already there, nothing to swap
+		return p.newSwapResult(zeroForOne, true, p.newSwapState(constants.Zero, targetSqrtPriceX64), o)
+	}
+
+	// an unbounded exact input only stops at the price limit, so the input is whatever it takes to get there
+	state, err := p.swap(zeroForOne, utils.MaxUint256, targetSqrtPriceX64, o)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return FromRawAmount(inputToken, inputAmount), pool, nil
+	state.amountSpecifiedRemaining = constants.Zero
+	return p.newSwapResult(zeroForOne, true, state, o)
 }
 
 /**
- * Executes a swap
+ * Given a target price of one pool token in terms of the other, return the input amount, including fees, needed to
+ * move the pool to that price, see GetInputAmountToSqrtPrice
+ * @param price The price to move the pool to, with either token as the base currency
+ * @param opts Options such as WithTrace
+ * @returns The swap result, including the input amount and the pool with updated state
+ */
+func (p *Pool) GetInputAmountToPrice(price *Price, opts ...SwapOption) (*SwapResult, error) {
+	targetSqrtPriceX64, err := p.sqrtRatioX64OfPrice(price)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetInputAmountToSqrtPrice(targetSqrtPriceX64, opts...)
+}
+
+// sqrtRatioX64OfPrice converts a price of either pool token into the Q64.64 sqrt price of token1 over token0
+func (p *Pool) sqrtRatioX64OfPrice(price *Price) (*big.Int, error) {
+	switch {
+	case price.BaseCurrency.Equal(p.Token0) && price.QuoteCurrency.Equal(p.Token1):
+		return utils.EncodeSqrtRatioX64(price.Numerator, price.Denominator), nil
+	case price.BaseCurrency.Equal(p.Token1) && price.QuoteCurrency.Equal(p.Token0):
+		return utils.EncodeSqrtRatioX64(price.Denominator, price.Numerator), nil
+	}
+	return nil, ErrTokenNotInvolved
+}
+
+// swapState keeps track of a swap while it walks the ticks
+type swapState struct {
+	amountSpecifiedRemaining *big.Int
+	sqrtPriceX64             *big.Int
+	sqrtPriceLimitX64        *big.Int
+	tick                     int
+	liquidity                *big.Int
+
+	amountIn     *big.Int // including fees
+	amountOut    *big.Int
+	feeAmount    *big.Int
+	protocolFee  *big.Int
+	crossedTicks int
+	steps        []StepComputations
+}
+
+// newSwapState returns the state of a swap that has not moved the pool yet
+func (p *Pool) newSwapState(amountSpecified, sqrtPriceLimitX64 *big.Int) *swapState {
+	return &swapState{
+		amountSpecifiedRemaining: amountSpecified,
+		sqrtPriceX64:             p.SqrtRatioX64,
+		sqrtPriceLimitX64:        sqrtPriceLimitX64,
+		tick:                     p.TickCurrent,
+		liquidity:                p.Liquidity,
+		amountIn:                 constants.Zero,
+		amountOut:                constants.Zero,
+		feeAmount:                constants.Zero,
+		protocolFee:              constants.Zero,
+	}
+}
+
+// clone returns a copy of the state that can be advanced independently
+func (s *swapState) clone() *swapState {
+	c := *s
+	c.steps = append([]StepComputations(nil), s.steps...)
+	return &c
+}
+
+/**
+ * Resolves and validates the sqrt price limit of a swap
  * @param zeroForOne Whether the amount in is token0 or token1
- * @param amountSpecified The amount of the swap, which implicitly configures the swap as exact input (positive), or exact output (negative)
- * @param sqrtPriceLimitX64 The Q64.64 sqrt price limit. If zero for one, the price cannot be less than this value after the swap. If one for zero, the price cannot be greater than this value after the swap
- * @returns amountCalculated
- * @returns sqrtRatioX64
- * @returns liquidity
- * @returns tickCurrent
+ * @param sqrtPriceLimitX64 The Q64.64 sqrt price limit, nil for no limit
+ * @param opts The swap options, whose slippage tolerance replaces the limit
  */
-func (p *Pool) swap(zeroForOne bool, amountSpecified, sqrtPriceLimitX64 *big.Int) (amountCalCulated *big.Int, sqrtRatioX64 *big.Int, liquidity *big.Int, tickCurrent int, numCrossTick int, err error) {
+func (p *Pool) sqrtPriceLimit(zeroForOne bool, sqrtPriceLimitX64 *big.Int, opts *swapOptions) (*big.Int, error) {
+	if opts.slippageTolerance != nil {
+		if sqrtPriceLimitX64 != nil {
+			return nil, ErrPriceLimitAndTolerance
+		}
+		return p.sqrtPriceLimitForSlippage(zeroForOne, opts.slippageTolerance)
+	}
 	if sqrtPriceLimitX64 == nil {
 		if zeroForOne {
 			sqrtPriceLimitX64 = new(big.Int).Add(utils.MinSqrtRatio, constants.One)
@@ -162,114 +279,204 @@ func (p *Pool) swap(zeroForOne bool, amountSpecified, sqrtPriceLimitX64 *big.Int
 		}
 	}
 
+	if err := p.validateSqrtPriceLimit(zeroForOne, sqrtPriceLimitX64); err != nil {
+		return nil, err
+	}
+	return sqrtPriceLimitX64, nil
+}
+
+// validateSqrtPriceLimit checks that a sqrt price limit is within the valid range and on the side of the current
+// price the swap moves towards
+func (p *Pool) validateSqrtPriceLimit(zeroForOne bool, sqrtPriceLimitX64 *big.Int) error {
 	if zeroForOne {
 		if sqrtPriceLimitX64.Cmp(utils.MinSqrtRatio) < 0 {
-			return nil, nil, nil, 0, 0, ErrSqrtPriceLimitX64TooLow
+			return ErrSqrtPriceLimitX64TooLow
 		}
 		if sqrtPriceLimitX64.Cmp(p.SqrtRatioX64) >= 0 {
-			return nil, nil, nil, 0, 0, ErrSqrtPriceLimitX64TooHigh
+			return ErrSqrtPriceLimitX64TooHigh
 		}
 	} else {
 		if sqrtPriceLimitX64.Cmp(utils.MaxSqrtRatio) > 0 {
-			return nil, nil, nil, 0, 0, ErrSqrtPriceLimitX64TooHigh
+			return ErrSqrtPriceLimitX64TooHigh
 		}
 		if sqrtPriceLimitX64.Cmp(p.SqrtRatioX64) <= 0 {
-			return nil, nil, nil, 0, 0, ErrSqrtPriceLimitX64TooLow
+			return ErrSqrtPriceLimitX64TooLow
 		}
 	}
+	return nil
+}
+
+/**
+ * Executes a swap
+ * @param zeroForOne Whether the amount in is token0 or token1
+ * @param amountSpecified The amount of the swap, which implicitly configures the swap as exact input (positive), or exact output (negative)
+ * @param sqrtPriceLimitX64 The Q64.64 sqrt price limit. If zero for one, the price cannot be less than this value after the swap. If one for zero, the price cannot be greater than this value after the swap
+ * @param opts The swap options
+ * @returns The final state of the swap
+ */
+func (p *Pool) swap(zeroForOne bool, amountSpecified, sqrtPriceLimitX64 *big.Int, opts *swapOptions) (*swapState, error) {
+	sqrtPriceLimitX64, err := p.sqrtPriceLimit(zeroForOne, sqrtPriceLimitX64, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	exactInput := amountSpecified.Cmp(constants.Zero) >= 0
 
 	// keep track of swap state
-
-	state := struct {
-		amountSpecifiedRemaining *big.Int
-		amountCalculated         *big.Int
-		sqrtPriceX64             *big.Int
-		tick                     int
-		liquidity                *big.Int
-	}{
-		amountSpecifiedRemaining: amountSpecified,
-		amountCalculated:         constants.Zero,
-		sqrtPriceX64:             p.SqrtRatioX64,
-		tick:                     p.TickCurrent,
-		liquidity:                p.Liquidity,
-	}
+	state := p.newSwapState(amountSpecified, sqrtPriceLimitX64)
 
 	// start swap while loop
 	for state.amountSpecifiedRemaining.Cmp(constants.Zero) != 0 && state.sqrtPriceX64.Cmp(sqrtPriceLimitX64) != 0 {
-		var step StepComputations
-		step.sqrtPriceStartX64 = state.sqrtPriceX64
-
-		// because each iteration of the while loop rounds, we can't optimize this code (relative to the smart contract)
-		// by simply traversing to the next available tick, we instead need to exactly replicate
-		// tickBitmap.nextInitializedTickWithinOneWord
-		step.tickNext, step.initialized = p.TickDataProvider.NextInitializedTickWithinOneWord(state.tick, zeroForOne, p.TickSpacing)
-
-		if step.tickNext < utils.MinTick {
-			step.tickNext = utils.MinTick
-		} else if step.tickNext > utils.MaxTick {
-			step.tickNext = utils.MaxTick
+		step, targetValue, err := p.nextStep(state, zeroForOne)
+		if err != nil {
+			return nil, err
 		}
 
-		step.sqrtPriceNextX64, err = utils.GetSqrtRatioAtTick(step.tickNext)
+		var sqrtPriceX64 *big.Int
+		sqrtPriceX64, step.AmountIn, step.AmountOut, step.FeeAmount, err = utils.ComputeSwapStep(state.sqrtPriceX64, targetValue, state.liquidity, state.amountSpecifiedRemaining, p.Fee)
 		if err != nil {
-			return nil, nil, nil, 0, 0, err
-		}
-		var targetValue *big.Int
-		if zeroForOne {
-			if step.sqrtPriceNextX64.Cmp(sqrtPriceLimitX64) < 0 {
-				targetValue = sqrtPriceLimitX64
-			} else {
-				targetValue = step.sqrtPriceNextX64
-			}
-		} else {
-			if step.sqrtPriceNextX64.Cmp(sqrtPriceLimitX64) > 0 {
-				targetValue = sqrtPriceLimitX64
-			} else {
-				targetValue = step.sqrtPriceNextX64
-			}
+			return nil, err
 		}
 
-		state.sqrtPriceX64, step.amountIn, step.amountOut, step.feeAmount, err = utils.ComputeSwapStep(state.sqrtPriceX64, targetValue, state.liquidity, state.amountSpecifiedRemaining, p.Fee)
-		if err != nil {
-			return nil, nil, nil, 0, 0, err
+		if err := p.applyStep(state, step, sqrtPriceX64, zeroForOne, exactInput, opts); err != nil {
+			return nil, err
 		}
+	}
+	return state, nil
+}
 
-		if exactInput {
-			state.amountSpecifiedRemaining = new(big.Int).Sub(state.amountSpecifiedRemaining, new(big.Int).Add(step.amountIn, step.feeAmount))
-			state.amountCalculated = new(big.Int).Sub(state.amountCalculated, step.amountOut)
+/**
+ * Prepares the next step of a swap, which moves towards the next initialized tick or word boundary, capped by the price limit
+ * @param state The current state of the swap
+ * @param zeroForOne Whether the amount in is token0 or token1
+ * @returns The step, with the amounts left to compute
+ * @returns The sqrt price the step moves towards
+ */
+func (p *Pool) nextStep(state *swapState, zeroForOne bool) (StepComputations, *big.Int, error) {
+	var step StepComputations
+	var err error
+	step.SqrtPriceStartX64 = state.sqrtPriceX64
+	step.Liquidity = state.liquidity
+
+	// because each iteration of the while loop rounds, we can't optimize this code (relative to the smart contract)
+	// by simply traversing to the next available tick, we instead need to exactly replicate
+	// tickBitmap.nextInitializedTickWithinOneWord
+	step.TickNext, step.Initialized, err = p.TickDataProvider.NextInitializedTickWithinOneWord(state.tick, zeroForOne, p.TickSpacing)
+	if err != nil {
+		return step, nil, err
+	}
+
+	if step.TickNext < utils.MinTick {
+		step.TickNext = utils.MinTick
+	} else if step.TickNext > utils.MaxTick {
+		step.TickNext = utils.MaxTick
+	}
+
+	step.SqrtPriceNextX64, err = p.getSqrtRatioAtTick(step.TickNext)
+	if err != nil {
+		return step, nil, err
+	}
+	var targetValue *big.Int
+	if zeroForOne {
+		if step.SqrtPriceNextX64.Cmp(state.sqrtPriceLimitX64) < 0 {
+			targetValue = state.sqrtPriceLimitX64
 		} else {
-			state.amountSpecifiedRemaining = new(big.Int).Add(state.amountSpecifiedRemaining, step.amountOut)
-			state.amountCalculated = new(big.Int).Add(state.amountCalculated, new(big.Int).Add(step.amountIn, step.feeAmount))
+			targetValue = step.SqrtPriceNextX64
 		}
+	} else {
+		if step.SqrtPriceNextX64.Cmp(state.sqrtPriceLimitX64) > 0 {
+			targetValue = state.sqrtPriceLimitX64
+		} else {
+			targetValue = step.SqrtPriceNextX64
+		}
+	}
+	return step, targetValue, nil
+}
 
-		// TODO
-		if state.sqrtPriceX64.Cmp(step.sqrtPriceNextX64) == 0 {
-			// if the tick is initialized, run the tick transition
-			if step.initialized {
-				liquidityNet := p.TickDataProvider.GetTick(step.tickNext).LiquidityNet
-				// if we're moving leftward, we interpret liquidityNet as the opposite sign
-				// safe because liquidityNet cannot be type(int128).min
-				if zeroForOne {
-					liquidityNet = new(big.Int).Mul(liquidityNet, constants.NegativeOne)
-				}
-				state.liquidity = utils.AddDelta(state.liquidity, liquidityNet)
-
-				numCrossTick += 1
+/**
+ * Books a computed step into the state of a swap and runs the tick transition when the step ends on the next tick
+ * @param state The state of the swap to advance
+ * @param step The step, with the amounts computed
+ * @param sqrtPriceX64 The sqrt price at the end of the step
+ * @param zeroForOne Whether the amount in is token0 or token1
+ * @param exactInput Whether the specified amount is the input or the output
+ * @param opts The swap options
+ */
+func (p *Pool) applyStep(state *swapState, step StepComputations, sqrtPriceX64 *big.Int, zeroForOne, exactInput bool, opts *swapOptions) error {
+	state.sqrtPriceX64 = sqrtPriceX64
+
+	if exactInput {
+		state.amountSpecifiedRemaining = new(big.Int).Sub(state.amountSpecifiedRemaining, new(big.Int).Add(step.AmountIn, step.FeeAmount))
+	} else {
+		state.amountSpecifiedRemaining = new(big.Int).Add(state.amountSpecifiedRemaining, step.AmountOut)
+	}
+	state.amountIn = new(big.Int).Add(state.amountIn, new(big.Int).Add(step.AmountIn, step.FeeAmount))
+	state.amountOut = new(big.Int).Add(state.amountOut, step.AmountOut)
+	state.feeAmount = new(big.Int).Add(state.feeAmount, step.FeeAmount)
+	if p.ProtocolFeeRate > 0 {
+		protocolFee := utils.MulDivRoundingUp(step.FeeAmount, new(big.Int).SetUint64(p.ProtocolFeeRate), new(big.Int).SetUint64(constants.ProtocolFeeDenominator))
+		state.protocolFee = new(big.Int).Add(state.protocolFee, protocolFee)
+	}
+
+	// TODO
+	if state.sqrtPriceX64.Cmp(step.SqrtPriceNextX64) == 0 {
+		// if the tick is initialized, run the tick transition
+		if step.Initialized {
+			tick, err := p.TickDataProvider.GetTick(step.TickNext)
+			if err != nil {
+				return err
 			}
+			liquidityNet := tick.LiquidityNet
+			// if we're moving leftward, we interpret liquidityNet as the opposite sign
+			// safe because liquidityNet cannot be type(int128).min
 			if zeroForOne {
-				state.tick = step.tickNext - 1
-			} else {
-				state.tick = step.tickNext
-			}
-		} else if state.sqrtPriceX64.Cmp(step.sqrtPriceStartX64) != 0 {
-			// recompute unless we're on a lower tick boundary (i.e. already transitioned ticks), and haven't moved
-			state.tick, err = utils.GetTickAtSqrtRatio(state.sqrtPriceX64)
-			if err != nil {
-				return nil, nil, nil, 0, 0, err
+				liquidityNet = new(big.Int).Mul(liquidityNet, constants.NegativeOne)
 			}
+			state.liquidity = utils.AddDelta(state.liquidity, liquidityNet)
+
+			state.crossedTicks += 1
+		}
+		if zeroForOne {
+			state.tick = step.TickNext - 1
+		} else {
+			state.tick = step.TickNext
+		}
+	} else if state.sqrtPriceX64.Cmp(step.SqrtPriceStartX64) != 0 {
+		// recompute unless we're on a lower tick boundary (i.e. already transitioned ticks), and haven't moved
+		var err error
+		state.tick, err = p.getTickAtSqrtRatio(state.sqrtPriceX64)
+		if err != nil {
+			return err
 		}
 	}
-	return state.amountCalculated, state.sqrtPriceX64, state.liquidity, state.tick, numCrossTick, nil
+
+	if opts.trace {
+		state.steps = append(state.steps, step)
+	}
+	return nil
+}
+
+// withState returns a copy of the pool with the given price, liquidity and tick, sharing tick data, protocol fee rate and lookup table
+func (p *Pool) withState(sqrtRatioX64, liquidity *big.Int, tickCurrent int) (*Pool, error) {
+	pool, err := NewPool(p.Token0, p.Token1, p.Fee, p.TickSpacing, sqrtRatioX64, liquidity, tickCurrent, p.TickDataProvider)
+	if err != nil {
+		return nil, err
+	}
+	pool.ProtocolFeeRate = p.ProtocolFeeRate
+	pool.SqrtRatioTable = p.SqrtRatioTable
+	return pool, nil
+}
+
+func (p *Pool) getSqrtRatioAtTick(tick int) (*big.Int, error) {
+	if p.SqrtRatioTable != nil {
+		return p.SqrtRatioTable.GetSqrtRatioAtTick(tick)
+	}
+	return utils.GetSqrtRatioAtTick(tick)
+}
+
+func (p *Pool) getTickAtSqrtRatio(sqrtRatioX64 *big.Int) (int, error) {
+	if p.SqrtRatioTable != nil {
+		return p.SqrtRatioTable.GetTickAtSqrtRatio(sqrtRatioX64)
+	}
+	return utils.GetTickAtSqrtRatio(sqrtRatioX64)
 }
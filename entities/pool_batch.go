@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+var ErrAmountsNotSorted = errors.New("amounts must be sorted in ascending order")
+
+/**
+ * Given a list of input amounts of the same token, sorted in ascending order, return the swap result of each amount as
+ * GetOutputAmount would, walking the ticks only once. Steps that run to the next tick are identical for every amount
+ * large enough to complete them, so each is computed once and shared by all amounts still swapping.
+ * @param inputAmounts The input amounts for which to quote the output amounts, in ascending order
+ * @param sqrtPriceLimitX64 The Q64.64 sqrt price limit
+ * @param opts Options such as WithTrace or WithRequireFullFill
+ * @returns The swap result of each input amount, in the same order
+ */
+func (p *Pool) GetOutputAmounts(inputAmounts []*CurrencyAmount, sqrtPriceLimitX64 *big.Int, opts ...SwapOption) ([]*SwapResult, error) {
+	if len(inputAmounts) == 0 {
+		return nil, nil
+	}
+	currency := inputAmounts[0].Currency
+	if !(currency.IsToken() && p.InvolvesToken(currency.Wrapped())) {
+		return nil, ErrTokenNotInvolved
+	}
+	amounts := make([]*big.Int, len(inputAmounts))
+	for i, inputAmount := range inputAmounts {
+		if !inputAmount.Currency.Equal(currency) {
+			return nil, ErrDifferentCurrencies
+		}
+		amounts[i] = inputAmount.Quotient()
+		if i > 0 && amounts[i].Cmp(amounts[i-1]) < 0 {
+			return nil, ErrAmountsNotSorted
+		}
+	}
+
+	o := newSwapOptions(opts)
+	zeroForOne := currency.Equal(p.Token0)
+	sqrtPriceLimitX64, err := p.sqrtPriceLimit(zeroForOne, sqrtPriceLimitX64, o)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SwapResult, len(amounts))
+	finish := func(i int, state *swapState) (err error) {
+		state.amountSpecifiedRemaining = new(big.Int).Sub(amounts[i], state.amountIn)
+		results[i], err = p.newSwapResult(zeroForOne, true, state, o)
+		return err
+	}
+
+	// the state shared by every amount still swapping, pending is the smallest of them
+	shared := p.newSwapState(nil, sqrtPriceLimitX64)
+	pending := 0
+	var step *StepComputations
+	var targetValue *big.Int
+	for pending < len(amounts) {
+		remaining := new(big.Int).Sub(amounts[pending], shared.amountIn)
+		if remaining.Cmp(constants.Zero) == 0 {
+			if err := finish(pending, shared.clone()); err != nil {
+				return nil, err
+			}
+			pending++
+			continue
+		}
+		if shared.sqrtPriceX64.Cmp(sqrtPriceLimitX64) == 0 {
+			break
+		}
+
+		// the next step only depends on the shared state, reuse it until the shared state moves
+		if step == nil {
+			next, target, err := p.nextStep(shared, zeroForOne)
+			if err != nil {
+				return nil, err
+			}
+			step, targetValue = &next, target
+		}
+
+		computed := *step
+		var sqrtPriceX64 *big.Int
+		sqrtPriceX64, computed.AmountIn, computed.AmountOut, computed.FeeAmount, err = utils.ComputeSwapStep(shared.sqrtPriceX64, targetValue, shared.liquidity, remaining, p.Fee)
+		if err != nil {
+			return nil, err
+		}
+
+		if sqrtPriceX64.Cmp(targetValue) == 0 {
+			// the smallest amount completes the step, so does every larger one with the same amounts
+			shared.amountSpecifiedRemaining = remaining
+			if err := p.applyStep(shared, computed, sqrtPriceX64, zeroForOne, true, o); err != nil {
+				return nil, err
+			}
+			step = nil
+		} else {
+			// the smallest amount runs out within the step
+			state := shared.clone()
+			state.amountSpecifiedRemaining = remaining
+			if err := p.applyStep(state, computed, sqrtPriceX64, zeroForOne, true, o); err != nil {
+				return nil, err
+			}
+			if err := finish(pending, state); err != nil {
+				return nil, err
+			}
+			pending++
+		}
+	}
+
+	// the price limit stopped the remaining amounts
+	for ; pending < len(amounts); pending++ {
+		if err := finish(pending, shared.clone()); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLayeredTestPool returns a pool with several overlapping positions so that swaps cross initialized ticks
+func newLayeredTestPool() *Pool {
+	spacing := constants.TickSpacings[constants.FeeLow]
+	ticks := []Tick{
+		{Index: NearestUsableTick(utils.MinTick, spacing), LiquidityNet: OneEther, LiquidityGross: OneEther},
+		{Index: -200, LiquidityNet: new(big.Int).Mul(OneEther, big.NewInt(2)), LiquidityGross: new(big.Int).Mul(OneEther, big.NewInt(2))},
+		{Index: -20, LiquidityNet: new(big.Int).Mul(OneEther, big.NewInt(5)), LiquidityGross: new(big.Int).Mul(OneEther, big.NewInt(5))},
+		{Index: 30, LiquidityNet: new(big.Int).Mul(OneEther, big.NewInt(-5)), LiquidityGross: new(big.Int).Mul(OneEther, big.NewInt(5))},
+		{Index: 3000, LiquidityNet: new(big.Int).Mul(OneEther, big.NewInt(-2)), LiquidityGross: new(big.Int).Mul(OneEther, big.NewInt(2))},
+		{Index: NearestUsableTick(utils.MaxTick, spacing), LiquidityNet: new(big.Int).Neg(OneEther), LiquidityGross: OneEther},
+	}
+	p, err := NewTickListDataProvider(ticks, spacing)
+	if err != nil {
+		panic(err)
+	}
+	pool, err := NewPool(USDC, DAI, constants.FeeLow, spacing, utils.EncodeSqrtRatioX64(constants.One, constants.One), new(big.Int).Mul(OneEther, big.NewInt(8)), 0, p)
+	if err != nil {
+		panic(err)
+	}
+	return pool
+}
+
+func TestGetOutputAmounts(t *testing.T) {
+	pool := newLayeredTestPool()
+	limit, _ := utils.GetSqrtRatioAtTick(-5000)
+
+	raw := []int64{0, 1, 100, 100, 12345, 1e15, 2e16, 2e16 + 1, 1e17, 5e17, 1e18}
+	for _, token := range []*Token{USDC, DAI} {
+		amounts := make([]*CurrencyAmount, len(raw))
+		for i, r := range raw {
+			amounts[i] = FromRawAmount(token, new(big.Int).Mul(big.NewInt(r), big.NewInt(3)))
+		}
+		var sqrtPriceLimitX64 *big.Int
+		if token == USDC {
+			sqrtPriceLimitX64 = limit
+		}
+
+		results, err := pool.GetOutputAmounts(amounts, sqrtPriceLimitX64, WithTrace())
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, results, len(amounts))
+		for i, amount := range amounts {
+			want, err := pool.GetOutputAmount(amount, sqrtPriceLimitX64, WithTrace())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := results[i]
+			assert.Equal(t, want.AmountIn.Quotient(), got.AmountIn.Quotient(), "amount in of %s", amount.Quotient())
+			assert.Equal(t, want.AmountOut.Quotient(), got.AmountOut.Quotient(), "amount out of %s", amount.Quotient())
+			assert.Equal(t, want.FeeAmount.Quotient(), got.FeeAmount.Quotient())
+			assert.Equal(t, want.AmountRemaining.Quotient(), got.AmountRemaining.Quotient())
+			assert.Equal(t, want.SqrtRatioX64, got.SqrtRatioX64)
+			assert.Equal(t, want.TickCurrent, got.TickCurrent)
+			assert.Equal(t, want.Liquidity, got.Liquidity)
+			assert.Equal(t, want.CrossedTicks, got.CrossedTicks)
+			assert.Equal(t, want.PriceLimitReached, got.PriceLimitReached)
+			assert.Equal(t, want.Steps, got.Steps)
+		}
+	}
+
+	_, err := pool.GetOutputAmounts([]*CurrencyAmount{FromRawAmount(USDC, big.NewInt(2)), FromRawAmount(USDC, big.NewInt(1))}, nil)
+	assert.ErrorIs(t, err, ErrAmountsNotSorted)
+	_, err = pool.GetOutputAmounts([]*CurrencyAmount{FromRawAmount(USDC, big.NewInt(1)), FromRawAmount(DAI, big.NewInt(2))}, nil)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+	_, err = pool.GetOutputAmounts([]*CurrencyAmount{FromRawAmount(WETH9[1], big.NewInt(1))}, nil)
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
+	_, err = pool.GetOutputAmounts([]*CurrencyAmount{FromRawAmount(USDC, OneEther)}, limit, WithRequireFullFill())
+	assert.ErrorIs(t, err, ErrPartialFill)
+}
+
+func BenchmarkGetOutputAmounts(b *testing.B) {
+	pool := newLayeredTestPool()
+	amounts := make([]*CurrencyAmount, 50)
+	for i := range amounts {
+		amounts[i] = FromRawAmount(USDC, new(big.Int).Mul(big.NewInt(int64(i+1)), big.NewInt(1e16)))
+	}
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = pool.GetOutputAmounts(amounts, nil)
+		}
+	})
+	b.Run("individual", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, amount := range amounts {
+				_, _ = pool.GetOutputAmount(amount, nil)
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+var (
+	ErrTicksNotListable      = errors.New("tick data provider cannot list its ticks")
+	ErrInvalidTickRange      = errors.New("invalid tick range")
+	ErrInvalidLiquidity      = errors.New("liquidity must be greater than zero")
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+)
+
+/**
+ * Returns a new pool with liquidity added to a tick range, as if a position was opened or increased. The pool's tick
+ * data provider must be a TickLister, the new pool gets a new TickListDataProvider with the updated ticks.
+ * @param tickLower The lower tick of the range
+ * @param tickUpper The upper tick of the range
+ * @param liquidity The liquidity to add
+ */
+func (p *Pool) ApplyMint(tickLower, tickUpper int, liquidity *big.Int) (*Pool, error) {
+	return p.applyLiquidityDelta(tickLower, tickUpper, liquidity, false)
+}
+
+/**
+ * Returns a new pool with liquidity removed from a tick range, as if a position was decreased or closed. Ticks left
+ * without any liquidity are uninitialized, see ApplyMint.
+ * @param tickLower The lower tick of the range
+ * @param tickUpper The upper tick of the range
+ * @param liquidity The liquidity to remove
+ */
+func (p *Pool) ApplyBurn(tickLower, tickUpper int, liquidity *big.Int) (*Pool, error) {
+	return p.applyLiquidityDelta(tickLower, tickUpper, liquidity, true)
+}
+
+func (p *Pool) applyLiquidityDelta(tickLower, tickUpper int, liquidity *big.Int, burn bool) (*Pool, error) {
+	if tickLower >= tickUpper || tickLower < utils.MinTick || tickUpper > utils.MaxTick {
+		return nil, ErrInvalidTickRange
+	}
+	if tickLower%p.TickSpacing != 0 || tickUpper%p.TickSpacing != 0 {
+		return nil, ErrInvalidTickSpacing
+	}
+	if liquidity.Cmp(constants.Zero) <= 0 {
+		return nil, ErrInvalidLiquidity
+	}
+	lister, ok := p.TickDataProvider.(TickLister)
+	if !ok {
+		return nil, ErrTicksNotListable
+	}
+
+	delta := liquidity
+	if burn {
+		delta = new(big.Int).Neg(liquidity)
+	}
+
+	ticks, err := updateTick(lister.Ticks(), tickLower, delta, false)
+	if err != nil {
+		return nil, err
+	}
+	ticks, err = updateTick(ticks, tickUpper, delta, true)
+	if err != nil {
+		return nil, err
+	}
+
+	poolLiquidity := p.Liquidity
+	if tickLower <= p.TickCurrent && p.TickCurrent < tickUpper {
+		poolLiquidity = new(big.Int).Add(p.Liquidity, delta)
+		if poolLiquidity.Sign() < 0 {
+			return nil, ErrInsufficientLiquidity
+		}
+	}
+
+	provider, err := NewTickListDataProvider(ticks, p.TickSpacing)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := p.withState(p.SqrtRatioX64, poolLiquidity, p.TickCurrent)
+	if err != nil {
+		return nil, err
+	}
+	pool.TickDataProvider = provider
+	return pool, nil
+}
+
+/**
+ * Returns a copy of the sorted ticks with the liquidity delta applied to a tick, initializing or uninitializing it as needed
+ * @param ticks The sorted ticks, not modified
+ * @param index The index of the tick to update
+ * @param delta The liquidity added (positive) or removed (negative)
+ * @param upper Whether the tick is the upper tick of the range, which subtracts the delta from its net liquidity
+ */
+func updateTick(ticks []Tick, index int, delta *big.Int, upper bool) ([]Tick, error) {
+	i := sort.Search(len(ticks), func(i int) bool { return ticks[i].Index >= index })
+	found := i < len(ticks) && ticks[i].Index == index
+
+	tick := Tick{Index: index, LiquidityGross: constants.Zero, LiquidityNet: constants.Zero}
+	if found {
+		tick = ticks[i]
+	}
+	liquidityGross := new(big.Int).Add(tick.LiquidityGross, delta)
+	if liquidityGross.Sign() < 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+	liquidityNet := new(big.Int).Add(tick.LiquidityNet, delta)
+	if upper {
+		liquidityNet = new(big.Int).Sub(tick.LiquidityNet, delta)
+	}
+
+	updated := make([]Tick, 0, len(ticks)+1)
+	updated = append(updated, ticks[:i]...)
+	if liquidityGross.Sign() > 0 {
+		updated = append(updated, Tick{Index: index, LiquidityGross: liquidityGross, LiquidityNet: liquidityNet})
+	}
+	if found {
+		i++
+	}
+	return append(updated, ticks[i:]...), nil
+}
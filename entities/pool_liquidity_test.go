@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMintAndBurn(t *testing.T) {
+	pool := newLayeredTestPool()
+	original := pool.TickDataProvider.(TickLister).Ticks()
+	liquidity := big.NewInt(1e9)
+
+	// in range, new ticks
+	minted, err := pool.ApplyMint(-100, 100, liquidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, new(big.Int).Add(pool.Liquidity, liquidity), minted.Liquidity)
+	lower, err := minted.TickDataProvider.GetTick(-100)
+	assert.NoError(t, err)
+	assert.Equal(t, liquidity, lower.LiquidityGross)
+	assert.Equal(t, liquidity, lower.LiquidityNet)
+	upper, err := minted.TickDataProvider.GetTick(100)
+	assert.NoError(t, err)
+	assert.Equal(t, liquidity, upper.LiquidityGross)
+	assert.Equal(t, new(big.Int).Neg(liquidity), upper.LiquidityNet)
+	assert.Len(t, minted.TickDataProvider.(TickLister).Ticks(), len(original)+2)
+	assert.Equal(t, original, pool.TickDataProvider.(TickLister).Ticks(), "the original pool is not modified")
+
+	// out of range, existing ticks
+	above, err := minted.ApplyMint(30, 3000, liquidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, minted.Liquidity, above.Liquidity)
+	tick, err := above.TickDataProvider.GetTick(30)
+	assert.NoError(t, err)
+	before, err := minted.TickDataProvider.GetTick(30)
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Add(before.LiquidityGross, liquidity), tick.LiquidityGross)
+	assert.Equal(t, new(big.Int).Add(before.LiquidityNet, liquidity), tick.LiquidityNet)
+
+	// burning everything restores the original state
+	burned, err := above.ApplyBurn(30, 3000, liquidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	burned, err = burned.ApplyBurn(-100, 100, liquidity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pool.Liquidity, burned.Liquidity)
+	assert.Equal(t, original, burned.TickDataProvider.(TickLister).Ticks())
+
+	_, err = pool.ApplyBurn(-100, 100, liquidity)
+	assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+	_, err = pool.ApplyMint(100, -100, liquidity)
+	assert.ErrorIs(t, err, ErrInvalidTickRange)
+	_, err = pool.ApplyMint(-105, 100, liquidity)
+	assert.ErrorIs(t, err, ErrInvalidTickSpacing)
+	_, err = pool.ApplyMint(-100, 100, big.NewInt(0))
+	assert.ErrorIs(t, err, ErrInvalidLiquidity)
+}
@@ -86,21 +86,21 @@ func TestGetOutputAmount(t *testing.T) {
 
 	// USDC -> DAI
 	inputAmount := FromRawAmount(USDC, big.NewInt(100))
-	outputAmount, _, _, err := pool.GetOutputAmount(inputAmount, nil)
+	result, err := pool.GetOutputAmount(inputAmount, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.True(t, outputAmount.Currency.Equal(DAI))
-	assert.Equal(t, outputAmount.Quotient(), big.NewInt(98))
+	assert.True(t, result.AmountOut.Currency.Equal(DAI))
+	assert.Equal(t, result.AmountOut.Quotient(), big.NewInt(98))
 
 	// DAI -> USDC
 	inputAmount = FromRawAmount(DAI, big.NewInt(100))
-	outputAmount, _, _, err = pool.GetOutputAmount(inputAmount, nil)
+	result, err = pool.GetOutputAmount(inputAmount, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.True(t, outputAmount.Currency.Equal(USDC))
-	assert.Equal(t, outputAmount.Quotient(), big.NewInt(98))
+	assert.True(t, result.AmountOut.Currency.Equal(USDC))
+	assert.Equal(t, result.AmountOut.Quotient(), big.NewInt(98))
 }
 
 func TestGetInputAmount(t *testing.T) {
@@ -108,19 +108,205 @@ func TestGetInputAmount(t *testing.T) {
 
 	// USDC -> DAI
 	outputAmount := FromRawAmount(DAI, big.NewInt(98))
-	inputAmount, _, err := pool.GetInputAmount(outputAmount, nil)
+	result, err := pool.GetInputAmount(outputAmount, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.True(t, inputAmount.Currency.Equal(USDC))
-	assert.Equal(t, inputAmount.Quotient(), big.NewInt(100))
+	assert.True(t, result.AmountIn.Currency.Equal(USDC))
+	assert.Equal(t, result.AmountIn.Quotient(), big.NewInt(100))
 
 	// DAI -> USDC
 	outputAmount = FromRawAmount(USDC, big.NewInt(98))
-	inputAmount, _, err = pool.GetInputAmount(outputAmount, nil)
+	result, err = pool.GetInputAmount(outputAmount, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.True(t, inputAmount.Currency.Equal(DAI))
-	assert.Equal(t, inputAmount.Quotient(), big.NewInt(100))
+	assert.True(t, result.AmountIn.Currency.Equal(DAI))
+	assert.Equal(t, result.AmountIn.Quotient(), big.NewInt(100))
+}
+
+func TestSwapWithSqrtRatioTable(t *testing.T) {
+	pool := newTestPool()
+	table, err := utils.SqrtRatioTableFor(pool.TickSpacing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tabled := *pool
+	tabled.SqrtRatioTable = table
+
+	for _, amount := range []*CurrencyAmount{FromRawAmount(USDC, big.NewInt(100)), FromRawAmount(DAI, OneEther)} {
+		want, err := pool.GetOutputAmount(amount, nil)
+		assert.NoError(t, err)
+		got, err := tabled.GetOutputAmount(amount, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, want.AmountOut.Quotient(), got.AmountOut.Quotient())
+		assert.Equal(t, want.SqrtRatioX64, got.SqrtRatioX64)
+		assert.Equal(t, want.TickCurrent, got.TickCurrent)
+		assert.Equal(t, want.CrossedTicks, got.CrossedTicks)
+		assert.Same(t, table, got.Pool.SqrtRatioTable, "table is carried over to the updated pool")
+	}
+}
+
+func BenchmarkSwapWithSqrtRatioTable(b *testing.B) {
+	pool := newTestPool()
+	table, err := utils.SqrtRatioTableFor(pool.TickSpacing)
+	if err != nil {
+		b.Fatal(err)
+	}
+	table.Len()
+	tabled := *pool
+	tabled.SqrtRatioTable = table
+
+	// moving 20000 ticks crosses about 8 words without initialized ticks in either direction, one for zero swaps stop
+	// below each word boundary, which is not a usable tick
+	for _, tt := range []struct {
+		name string
+		tick int
+	}{{"zeroForOne", -20000}, {"oneForZero", 20000}} {
+		target, _ := utils.GetSqrtRatioAtTick(tt.tick)
+		b.Run(tt.name+"/math", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = pool.GetInputAmountToSqrtPrice(target)
+			}
+		})
+		b.Run(tt.name+"/table", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = tabled.GetInputAmountToSqrtPrice(target)
+			}
+		})
+	}
+}
+
+func TestSwapResult(t *testing.T) {
+	pool := newTestPool()
+	pool.ProtocolFeeRate = 2000
+
+	result, err := pool.GetOutputAmount(FromRawAmount(USDC, big.NewInt(100)), nil, WithTrace())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(100), result.AmountIn.Quotient())
+	assert.Equal(t, big.NewInt(98), result.AmountOut.Quotient())
+	assert.True(t, result.FeeAmount.Currency.Equal(USDC))
+	assert.Equal(t, big.NewInt(1), result.FeeAmount.Quotient())
+	assert.Equal(t, big.NewInt(1), result.ProtocolFee.Quotient())
+	assert.Equal(t, 0, result.CrossedTicks)
+	assert.False(t, result.PriceLimitReached)
+	assert.Equal(t, constants.Zero, result.AmountRemaining.Quotient())
+	assert.Equal(t, OneEther, result.Liquidity)
+	assert.Equal(t, result.SqrtRatioX64, result.Pool.SqrtRatioX64)
+	assert.Equal(t, result.TickCurrent, result.Pool.TickCurrent)
+	assert.Equal(t, uint64(2000), result.Pool.ProtocolFeeRate)
+	// the first step stops at the word boundary at the current tick without moving the price
+	if assert.Len(t, result.Steps, 2) {
+		assert.Equal(t, constants.Zero, result.Steps[0].AmountIn)
+		step := result.Steps[1]
+		assert.Equal(t, pool.SqrtRatioX64, step.SqrtPriceStartX64)
+		assert.Equal(t, big.NewInt(99), step.AmountIn)
+		assert.Equal(t, big.NewInt(98), step.AmountOut)
+		assert.Equal(t, big.NewInt(1), step.FeeAmount)
+	}
+
+	result, err = pool.GetInputAmount(FromRawAmount(DAI, big.NewInt(98)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(100), result.AmountIn.Quotient())
+	assert.Equal(t, big.NewInt(98), result.AmountOut.Quotient())
+	assert.True(t, result.AmountRemaining.Currency.Equal(DAI))
+	assert.Empty(t, result.Steps, "steps are only recorded when tracing")
+}
+
+func TestPartialFill(t *testing.T) {
+	pool := newTestPool()
+	limit, _ := utils.GetSqrtRatioAtTick(-10)
+
+	// exact input
+	result, err := pool.GetOutputAmount(FromRawAmount(USDC, OneEther), limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.PriceLimitReached)
+	assert.True(t, result.PartiallyFilled())
+	assert.Equal(t, limit, result.SqrtRatioX64)
+	assert.True(t, result.AmountRemaining.Currency.Equal(USDC))
+	assert.Equal(t, OneEther, new(big.Int).Add(result.AmountIn.Quotient(), result.AmountRemaining.Quotient()), "consumed and unfilled input add up to the specified amount")
+
+	_, err = pool.GetOutputAmount(FromRawAmount(USDC, OneEther), limit, WithRequireFullFill())
+	assert.ErrorIs(t, err, ErrPartialFill)
+	var partialFill *PartialFillError
+	if assert.ErrorAs(t, err, &partialFill) {
+		assert.True(t, partialFill.ExactInput)
+		assert.Equal(t, result.AmountRemaining.Quotient(), partialFill.AmountRemaining.Quotient())
+	}
+
+	// exact output
+	result, err = pool.GetInputAmount(FromRawAmount(DAI, OneEther), limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, result.PartiallyFilled())
+	assert.True(t, result.AmountRemaining.Currency.Equal(DAI))
+	assert.Equal(t, OneEther, new(big.Int).Add(result.AmountOut.Quotient(), result.AmountRemaining.Quotient()), "received and unfilled output add up to the specified amount")
+
+	_, err = pool.GetInputAmount(FromRawAmount(DAI, OneEther), limit, WithRequireFullFill())
+	if assert.ErrorAs(t, err, &partialFill) {
+		assert.False(t, partialFill.ExactInput)
+	}
+
+	// filled swaps are unaffected
+	_, err = pool.GetOutputAmount(FromRawAmount(USDC, big.NewInt(100)), limit, WithRequireFullFill())
+	assert.NoError(t, err)
+}
+
+func TestGetInputAmountToSqrtPrice(t *testing.T) {
+	pool := newTestPool()
+
+	for _, tick := range []int{-100, 2500} {
+		target, _ := utils.GetSqrtRatioAtTick(tick)
+		result, err := pool.GetInputAmountToSqrtPrice(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, target, result.SqrtRatioX64)
+		assert.True(t, result.PriceLimitReached)
+		assert.False(t, result.PartiallyFilled())
+
+		// swapping the quoted input reaches the target, give or take rounding of the sqrt price
+		swapped, err := pool.GetOutputAmount(result.AmountIn, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.LessOrEqual(t, new(big.Int).Abs(new(big.Int).Sub(target, swapped.SqrtRatioX64)).Int64(), int64(10))
+	}
+
+	target, _ := utils.GetSqrtRatioAtTick(-100)
+	result, _ := pool.GetInputAmountToSqrtPrice(target)
+	assert.True(t, result.AmountIn.Currency.Equal(USDC), "moving the price down takes token0 in")
+
+	result, err := pool.GetInputAmountToSqrtPrice(pool.SqrtRatioX64)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(0), result.AmountIn.Quotient(), "nothing to swap at the current price")
+
+	// the target is the limit, a tolerance would contradict it
+	onePercent := NewPercent(big.NewInt(1), big.NewInt(100))
+	_, err = pool.GetInputAmountToSqrtPrice(target, WithSlippageTolerance(onePercent))
+	assert.ErrorIs(t, err, ErrPriceLimitAndTolerance)
+	_, err = pool.GetInputAmountToPrice(NewPrice(USDC, DAI, big.NewInt(100), big.NewInt(99)), WithSlippageTolerance(onePercent))
+	assert.ErrorIs(t, err, ErrPriceLimitAndTolerance)
+
+	// prices of either token resolve to the same target
+	byToken0, err := pool.GetInputAmountToPrice(NewPrice(USDC, DAI, big.NewInt(100), big.NewInt(99)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byToken1, err := pool.GetInputAmountToPrice(NewPrice(DAI, USDC, big.NewInt(99), big.NewInt(100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, utils.EncodeSqrtRatioX64(big.NewInt(99), big.NewInt(100)), byToken0.SqrtRatioX64)
+	assert.Equal(t, byToken0.AmountIn.Quotient(), byToken1.AmountIn.Quotient())
+
+	_, err = pool.GetInputAmountToPrice(NewPrice(USDC, WETH9[1], big.NewInt(1), big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
 }
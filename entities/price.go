@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrDifferentCurrencies = errors.New("different currencies")
+)
+
+// Price is the price of a base currency in terms of a quote currency. The fraction is the raw ratio of quote to
+// base amounts, the Scalar adjusts it for the decimals of both currencies when formatting.
+type Price struct {
+	*Fraction
+	BaseCurrency  Currency  // input i.e. denominator
+	QuoteCurrency Currency  // output i.e. numerator
+	Scalar        *Fraction // used to adjust the raw fraction w/r/t the decimals of the {base,quote}Token
+}
+
+/**
+ * Construct a price, either with the base and quote currency amount, or the base and quote currency and the raw ratio
+ * @param baseCurrency the currency the price is for
+ * @param quoteCurrency the currency the price is denominated in
+ * @param denominator the raw amount of the base currency
+ * @param numerator the raw amount of the quote currency
+ */
+func NewPrice(baseCurrency, quoteCurrency Currency, denominator, numerator *big.Int) *Price {
+	return &Price{
+		Fraction:      NewFraction(numerator, denominator),
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		Scalar: NewFraction(
+			new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(baseCurrency.Decimals())), nil),
+			new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(quoteCurrency.Decimals())), nil)),
+	}
+}
+
+// Invert flips the price, switching the base and quote currency
+func (p *Price) Invert() *Price {
+	return NewPrice(p.QuoteCurrency, p.BaseCurrency, p.Numerator, p.Denominator)
+}
+
+/**
+ * Multiply the price by another price, returning a new price. The other price must have the same base currency as this price's quote currency
+ * @param other the other price
+ */
+func (p *Price) Multiply(other *Price) (*Price, error) {
+	if !p.QuoteCurrency.Equal(other.BaseCurrency) {
+		return nil, ErrDifferentCurrencies
+	}
+	fraction := p.Fraction.Multiply(other.Fraction)
+	return NewPrice(p.BaseCurrency, other.QuoteCurrency, fraction.Denominator, fraction.Numerator), nil
+}
+
+/**
+ * Return the amount of quote currency corresponding to a given amount of the base currency
+ * @param currencyAmount the amount of base currency to quote against the price
+ */
+func (p *Price) Quote(currencyAmount *CurrencyAmount) (*CurrencyAmount, error) {
+	if !currencyAmount.Currency.Equal(p.BaseCurrency) {
+		return nil, ErrDifferentCurrencies
+	}
+	result := p.Fraction.Multiply(currencyAmount.Fraction)
+	return FromFractionalAmount(p.QuoteCurrency, result.Numerator, result.Denominator), nil
+}
+
+// adjustedForDecimals gets the price adjusted for the decimals of the base and quote currency
+func (p *Price) adjustedForDecimals() *Fraction {
+	return p.Fraction.Multiply(p.Scalar)
+}
+
+// ToSignificant returns the decimal adjusted price as a string with the most significant digits
+func (p *Price) ToSignificant(significantDigits int32) string {
+	return p.adjustedForDecimals().ToSignificant(significantDigits)
+}
+
+// ToFixed returns the decimal adjusted price as a string with the specified number of digits after the decimal
+func (p *Price) ToFixed(decimalPlaces int32) string {
+	return p.adjustedForDecimals().ToFixed(decimalPlaces)
+}
+
+/**
+ * Formats the decimal adjusted price exactly with the given number of digits after the decimal point
+ * @param decimalPlaces the number of digits after the decimal point
+ * @param opts the rounding and layout of the result
+ */
+func (p *Price) FormatFixed(decimalPlaces int32, opts FormatOptions) string {
+	return p.adjustedForDecimals().FormatFixed(decimalPlaces, opts)
+}
+
+/**
+ * Formats the decimal adjusted price exactly with the given number of significant digits
+ * @param significantDigits the number of significant digits
+ * @param opts the rounding and layout of the result
+ */
+func (p *Price) FormatSignificant(significantDigits int32, opts FormatOptions) string {
+	return p.adjustedForDecimals().FormatSignificant(significantDigits, opts)
+}
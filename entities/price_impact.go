@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+var (
+	ErrZeroInput                = errors.New("input amount is worth nothing at the mid price")
+	ErrInvalidSlippageTolerance = errors.New("slippage tolerance must be between 0 and 100%")
+)
+
+/**
+ * Returns the price impact of a trade: the share of the output quoted at the mid price that the trade does not get
+ * @param midPrice the mid price before the trade, of the input currency in the output currency
+ * @param inputAmount the input of the trade
+ * @param outputAmount the output of the trade
+ */
+func ComputePriceImpact(midPrice *Price, inputAmount, outputAmount *CurrencyAmount) (*Percent, error) {
+	if !outputAmount.Currency.Equal(midPrice.QuoteCurrency) {
+		return nil, ErrDifferentCurrencies
+	}
+	quotedOutputAmount, err := midPrice.Quote(inputAmount)
+	if err != nil {
+		return nil, err
+	}
+	if quotedOutputAmount.Numerator.Sign() == 0 {
+		return nil, ErrZeroInput
+	}
+	impact := quotedOutputAmount.Fraction.Subtract(outputAmount.Fraction).Divide(quotedOutputAmount.Fraction)
+	return NewPercent(impact.Numerator, impact.Denominator), nil
+}
+
+/**
+ * Quotes an exact input swap and returns its price impact against the pool's mid price before the swap. The
+ * execution price includes the swap fee, so even the smallest trade has an impact of about the fee.
+ * @param inputAmount the input of the swap
+ * @param opts Options such as WithRequireFullFill
+ * @returns the price impact and the swap result
+ */
+func (p *Pool) PriceImpact(inputAmount *CurrencyAmount, opts ...SwapOption) (*Percent, *SwapResult, error) {
+	if !(inputAmount.Currency.IsToken() && p.InvolvesToken(inputAmount.Currency.Wrapped())) {
+		return nil, nil, ErrTokenNotInvolved
+	}
+	midPrice, err := p.PriceOf(inputAmount.Currency.Wrapped())
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := p.GetOutputAmount(inputAmount, nil, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	impact, err := ComputePriceImpact(midPrice, result.AmountIn, result.AmountOut)
+	if err != nil {
+		return nil, nil, err
+	}
+	return impact, result, nil
+}
+
+// exposurePrecisionBits is the relative precision, as a power of two, to which SlippageExposure finds the worst price
+const exposurePrecisionBits = 32
+
+// SlippageExposure is how far the price can move against a trade before it executes without the trade failing its
+// minimum output
+type SlippageExposure struct {
+	AmountIn          *CurrencyAmount // the input of the trade
+	ExpectedAmountOut *CurrencyAmount // the output quoted at the current price
+	MinimumAmountOut  *CurrencyAmount // the least output the slippage tolerance accepts
+	PriceImpact       *Percent        // the price impact of the trade at the current price
+	MaxPriceMovement  *Percent        // the largest fall of the input token's mid price before the trade that it survives
+	WorstSqrtRatioX64 *big.Int        // the sqrt price after that fall
+	FrontrunAmountIn  *CurrencyAmount // the input of a swap ahead of the trade that causes that fall, including fees
+}
+
+/**
+ * Reports the exposure of an exact input trade with the given slippage tolerance: the pre-trade price movement
+ * against it, caused by a swap in the same direction executing first, that still leaves at least the minimum output.
+ * Each candidate price is reached with GetInputAmountToSqrtPrice and the trade quoted against the resulting pool. The
+ * candidates are bisected over the ticks, then within the last tick interval to a relative precision of 2^-32.
+ * @param inputAmount the input of the trade
+ * @param slippageTolerance the share of the expected output the trader accepts to lose, from 0 to 100%
+ */
+func (p *Pool) SlippageExposure(inputAmount *CurrencyAmount, slippageTolerance *Percent) (*SlippageExposure, error) {
+	if !validTolerance(slippageTolerance) {
+		return nil, ErrInvalidSlippageTolerance
+	}
+	impact, expected, err := p.PriceImpact(inputAmount, WithRequireFullFill())
+	if err != nil {
+		return nil, err
+	}
+	kept := NewFraction(big.NewInt(1), big.NewInt(1)).Subtract(slippageTolerance.Fraction).Multiply(expected.AmountOut.Fraction)
+	minimumAmountOut := FromRawAmount(expected.AmountOut.Currency, kept.Quotient())
+
+	// whether the trade still gets its minimum output after the pool is moved from the given pool to sqrtRatioX64,
+	// and that pool
+	survives := func(from *Pool, sqrtRatioX64 *big.Int) (bool, *Pool, error) {
+		moved, err := from.GetInputAmountToSqrtPrice(sqrtRatioX64)
+		if err != nil {
+			return false, nil, err
+		}
+		result, err := moved.Pool.GetOutputAmount(inputAmount, nil, WithRequireFullFill())
+		if errors.Is(err, ErrPartialFill) || errors.Is(err, ErrSqrtPriceLimitX64TooHigh) || errors.Is(err, ErrSqrtPriceLimitX64TooLow) {
+			// no room left to swap to the minimum output
+			return false, moved.Pool, nil
+		}
+		if err != nil {
+			return false, nil, err
+		}
+		return result.AmountOut.Quotient().Cmp(minimumAmountOut.Quotient()) >= 0, moved.Pool, nil
+	}
+
+	// the current price survives, search towards the bound of the adverse direction
+	zeroForOne := inputAmount.Currency.Equal(p.Token0)
+	bound := new(big.Int).Add(utils.MinSqrtRatio, constants.One)
+	goodTick, badTick := p.TickCurrent+1, utils.MinTick // the ticks just past the current price and the bound
+	if !zeroForOne {
+		bound = new(big.Int).Sub(utils.MaxSqrtRatio, constants.One)
+		goodTick, badTick = p.TickCurrent, utils.MaxTick
+	}
+	good, bad := p.SqrtRatioX64, bound
+	ok, _, err := survives(p, bound)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		good = bound
+	} else {
+		// bisect over the ticks first, then refine within the last tick interval to a relative precision far below what
+		// the reported percentage shows. Every candidate lies beyond the good end, so it is reached from the pool there.
+		base := p
+		for goodTick-badTick > 1 || badTick-goodTick > 1 {
+			tick := goodTick + (badTick-goodTick)/2
+			sqrtRatioX64, err := p.getSqrtRatioAtTick(tick)
+			if err != nil {
+				return nil, err
+			}
+			ok, moved, err := survives(base, sqrtRatioX64)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				goodTick, good, base = tick, sqrtRatioX64, moved
+			} else {
+				badTick, bad = tick, sqrtRatioX64
+			}
+		}
+
+		for new(big.Int).Sub(good, bad).CmpAbs(new(big.Int).Rsh(good, exposurePrecisionBits)) > 0 {
+			mid := new(big.Int).Add(good, bad)
+			mid.Rsh(mid, 1)
+			ok, moved, err := survives(base, mid)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				good, base = mid, moved
+			} else {
+				bad = mid
+			}
+		}
+	}
+	frontrun, err := p.GetInputAmountToSqrtPrice(good)
+	if err != nil {
+		return nil, err
+	}
+
+	// the input token's price falls with the square of the sqrt price ratio
+	before, after := new(big.Int).Mul(p.SqrtRatioX64, p.SqrtRatioX64), new(big.Int).Mul(good, good)
+	if !zeroForOne {
+		before, after = after, before
+	}
+	movement := NewFraction(new(big.Int).Sub(before, after), before).Reduce()
+
+	return &SlippageExposure{
+		AmountIn:          expected.AmountIn,
+		ExpectedAmountOut: expected.AmountOut,
+		MinimumAmountOut:  minimumAmountOut,
+		PriceImpact:       impact,
+		MaxPriceMovement:  NewPercent(movement.Numerator, movement.Denominator),
+		WorstSqrtRatioX64: good,
+		FrontrunAmountIn:  frontrun.AmountIn,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercent(t *testing.T) {
+	p := NewPercent(big.NewInt(1), big.NewInt(200))
+	assert.Equal(t, "0.5", p.ToSignificant(3))
+	assert.Equal(t, "0.50", p.ToFixed(2))
+}
+
+func TestComputePriceImpact(t *testing.T) {
+	midPrice := NewPrice(USDC, DAI, big.NewInt(1), big.NewInt(10))
+	impact, err := ComputePriceImpact(midPrice, FromRawAmount(USDC, big.NewInt(100)), FromRawAmount(DAI, big.NewInt(900)))
+	assert.NoError(t, err)
+	assert.Equal(t, "10", impact.ToSignificant(5))
+
+	// a better execution than the mid price is a negative impact
+	impact, err = ComputePriceImpact(midPrice, FromRawAmount(USDC, big.NewInt(100)), FromRawAmount(DAI, big.NewInt(1100)))
+	assert.NoError(t, err)
+	assert.Equal(t, "-10", impact.ToSignificant(5))
+
+	_, err = ComputePriceImpact(midPrice, FromRawAmount(DAI, big.NewInt(100)), FromRawAmount(DAI, big.NewInt(900)))
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+	_, err = ComputePriceImpact(midPrice, FromRawAmount(USDC, big.NewInt(100)), FromRawAmount(USDC, big.NewInt(900)))
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+	_, err = ComputePriceImpact(midPrice, FromRawAmount(USDC, big.NewInt(0)), FromRawAmount(DAI, big.NewInt(0)))
+	assert.ErrorIs(t, err, ErrZeroInput)
+}
+
+func TestPoolPriceImpact(t *testing.T) {
+	pool := newTestPool()
+
+	// a small trade pays about the 0.05% fee
+	impact, result, err := pool.PriceImpact(FromRawAmount(USDC, big.NewInt(1e12)))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.05", impact.ToSignificant(2))
+	assert.Equal(t, big.NewInt(1e12), result.AmountIn.Quotient())
+
+	// a large one moves the price too, in either direction
+	impact, _, err = pool.PriceImpact(FromRawAmount(USDC, big.NewInt(1e17)))
+	assert.NoError(t, err)
+	assert.Equal(t, "9.1", impact.ToSignificant(2))
+	impact, _, err = pool.PriceImpact(FromRawAmount(DAI, big.NewInt(1e17)))
+	assert.NoError(t, err)
+	assert.Equal(t, "9.1", impact.ToSignificant(2))
+
+	_, _, err = pool.PriceImpact(FromRawAmount(WETH9[1], big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
+}
+
+func TestSlippageExposure(t *testing.T) {
+	pool := newTestPool()
+	input := FromRawAmount(USDC, big.NewInt(1e16))
+
+	for _, input := range []*CurrencyAmount{input, FromRawAmount(DAI, big.NewInt(1e16))} {
+		exposure, err := pool.SlippageExposure(input, NewPercent(big.NewInt(1), big.NewInt(100)))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, input.Quotient(), exposure.AmountIn.Quotient())
+		assert.Equal(t, "0.99", exposure.MinimumAmountOut.Fraction.Divide(exposure.ExpectedAmountOut.Fraction).ToSignificant(2))
+
+		// about 1%, as the trade is small enough for the price impact to barely change with the price
+		assert.Equal(t, "1", exposure.MaxPriceMovement.ToSignificant(1))
+		assert.Equal(t, 1, exposure.FrontrunAmountIn.Quotient().Sign())
+
+		// the trade survives at the worst price and fails just beyond it
+		moved, err := pool.GetInputAmountToSqrtPrice(exposure.WorstSqrtRatioX64)
+		assert.NoError(t, err)
+		assert.Equal(t, exposure.FrontrunAmountIn.Quotient(), moved.AmountIn.Quotient())
+		result, err := moved.Pool.GetOutputAmount(input, nil)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, result.AmountOut.Quotient().Cmp(exposure.MinimumAmountOut.Quotient()), 0)
+
+		// the worst price is found to a relative precision of 2^-32
+		precision := new(big.Int).Rsh(exposure.WorstSqrtRatioX64, 32)
+		beyond := new(big.Int).Sub(exposure.WorstSqrtRatioX64, precision)
+		if input.Currency.Equal(DAI) {
+			beyond.Add(exposure.WorstSqrtRatioX64, precision)
+		}
+		moved, err = pool.GetInputAmountToSqrtPrice(beyond)
+		assert.NoError(t, err)
+		result, err = moved.Pool.GetOutputAmount(input, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, -1, result.AmountOut.Quotient().Cmp(exposure.MinimumAmountOut.Quotient()))
+	}
+
+	// without tolerance the price must not move at all, with full tolerance it can move next to the bound
+	exposure, err := pool.SlippageExposure(input, NewPercent(big.NewInt(0), big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000000", exposure.MaxPriceMovement.ToFixed(6), "up to rounding")
+	exposure, err = pool.SlippageExposure(input, NewPercent(big.NewInt(1), big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, "100", exposure.MaxPriceMovement.ToSignificant(3))
+	assert.Equal(t, 1, exposure.WorstSqrtRatioX64.Cmp(new(big.Int).Add(utils.MinSqrtRatio, constants.One)))
+
+	_, err = pool.SlippageExposure(input, NewPercent(big.NewInt(101), big.NewInt(100)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+	_, err = pool.SlippageExposure(input, NewPercent(big.NewInt(-1), big.NewInt(100)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrice(t *testing.T) {
+	price := NewPrice(USDC, DAI, big.NewInt(1e6), OneEther)
+	assert.Equal(t, "1", price.ToSignificant(5))
+	assert.Equal(t, "1.00", price.ToFixed(2))
+	assert.Equal(t, "1", price.Invert().ToSignificant(5))
+	assert.True(t, price.Invert().BaseCurrency.Equal(DAI))
+
+	quoted, err := price.Quote(FromRawAmount(USDC, big.NewInt(5e6)))
+	assert.NoError(t, err)
+	assert.True(t, quoted.Currency.Equal(DAI))
+	assert.Equal(t, "5", quoted.ToExact())
+	_, err = price.Quote(FromRawAmount(DAI, big.NewInt(5)))
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+
+	weth := NewPrice(DAI, WETH9[1], big.NewInt(2000), big.NewInt(1))
+	multiplied, err := price.Multiply(weth)
+	assert.NoError(t, err)
+	assert.True(t, multiplied.BaseCurrency.Equal(USDC))
+	assert.True(t, multiplied.QuoteCurrency.Equal(WETH9[1]))
+	assert.Equal(t, "0.0005", multiplied.ToSignificant(5))
+	_, err = price.Multiply(price)
+	assert.ErrorIs(t, err, ErrDifferentCurrencies)
+}
@@ -0,0 +1,243 @@
+package entities
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+var ErrInconsistentLiquidity = errors.New("tick liquidity is inconsistent with the pool liquidity")
+
+// profilePriceDigits is the number of significant digits of the prices in exported profiles
+const profilePriceDigits = 12
+
+// LiquidityRange is the liquidity in range between two consecutive initialized ticks
+type LiquidityRange struct {
+	TickLower  int
+	TickUpper  int
+	Liquidity  *big.Int
+	Amount0    *CurrencyAmount // the token0 the liquidity holds, above the current price
+	Amount1    *CurrencyAmount // the token1 the liquidity holds, below the current price
+	PriceLower *Price          // the price of token0 in token1 at the lower tick
+	PriceUpper *Price          // the price of token0 in token1 at the upper tick
+	Active     bool            // whether the current tick is in the range
+}
+
+// LiquidityProfile is the distribution of the liquidity of a pool over its initialized ticks, sorted by tick
+type LiquidityProfile struct {
+	Token0      *Token
+	Token1      *Token
+	TickCurrent int
+	Ranges      []LiquidityRange
+}
+
+/**
+ * Returns the liquidity profile of the pool, accumulating the liquidity net of the initialized ticks outward from
+ * the pool liquidity at the current tick. The ticks are listed at once from a TickLister, and otherwise found by
+ * walking the tick data provider word by word from the current tick to both ends of the valid tick range.
+ */
+func (p *Pool) LiquidityProfile() (*LiquidityProfile, error) {
+	ticks, err := p.initializedTicks()
+	if err != nil {
+		return nil, err
+	}
+	profile := &LiquidityProfile{Token0: p.Token0, Token1: p.Token1, TickCurrent: p.TickCurrent}
+	if len(ticks) < 2 {
+		return profile, nil
+	}
+
+	// range i is between ticks i and i+1, the pool liquidity is that of the range holding the current tick, which
+	// is -1 or len(ticks)-1 when the current tick is outside of the initialized ticks
+	active := -1
+	for active+1 < len(ticks) && ticks[active+1].Index <= p.TickCurrent {
+		active++
+	}
+	liquidity := make([]*big.Int, len(ticks)-1)
+	previous := p.Liquidity
+	for i := active + 1; i < len(liquidity); i++ {
+		previous = new(big.Int).Add(previous, ticks[i].LiquidityNet)
+		liquidity[i] = previous
+	}
+	if active >= 0 && active < len(liquidity) {
+		liquidity[active] = p.Liquidity
+	}
+	previous = p.Liquidity
+	for i := active - 1; i >= 0; i-- {
+		previous = new(big.Int).Sub(previous, ticks[i+1].LiquidityNet)
+		liquidity[i] = previous
+	}
+
+	profile.Ranges = make([]LiquidityRange, 0, len(liquidity))
+	for i, l := range liquidity {
+		if l.Sign() < 0 {
+			return nil, ErrInconsistentLiquidity
+		}
+		r, err := p.liquidityRange(ticks[i].Index, ticks[i+1].Index, l)
+		if err != nil {
+			return nil, err
+		}
+		r.Active = i == active
+		profile.Ranges = append(profile.Ranges, *r)
+	}
+	return profile, nil
+}
+
+// initializedTicks returns the initialized ticks of the pool sorted by index
+func (p *Pool) initializedTicks() ([]Tick, error) {
+	if lister, ok := p.TickDataProvider.(TickLister); ok {
+		return lister.Ticks(), nil
+	}
+
+	// at or below the current tick, found in descending order
+	var below []Tick
+	for tick := p.TickCurrent; tick >= utils.MinTick; {
+		next, initialized, err := p.TickDataProvider.NextInitializedTickWithinOneWord(tick, true, p.TickSpacing)
+		if err != nil {
+			return nil, err
+		}
+		if initialized && next >= utils.MinTick {
+			t, err := p.TickDataProvider.GetTick(next)
+			if err != nil {
+				return nil, err
+			}
+			below = append(below, t)
+		}
+		tick = next - 1
+	}
+	ticks := make([]Tick, 0, len(below))
+	for i := len(below) - 1; i >= 0; i-- {
+		ticks = append(ticks, below[i])
+	}
+
+	// above the current tick
+	for tick := p.TickCurrent; tick < utils.MaxTick; {
+		next, initialized, err := p.TickDataProvider.NextInitializedTickWithinOneWord(tick, false, p.TickSpacing)
+		if err != nil {
+			return nil, err
+		}
+		if initialized && next <= utils.MaxTick {
+			t, err := p.TickDataProvider.GetTick(next)
+			if err != nil {
+				return nil, err
+			}
+			ticks = append(ticks, t)
+		}
+		tick = next
+	}
+	return ticks, nil
+}
+
+func (p *Pool) liquidityRange(tickLower, tickUpper int, liquidity *big.Int) (*LiquidityRange, error) {
+	sqrtRatioLowerX64, err := p.getSqrtRatioAtTick(tickLower)
+	if err != nil {
+		return nil, err
+	}
+	sqrtRatioUpperX64, err := p.getSqrtRatioAtTick(tickUpper)
+	if err != nil {
+		return nil, err
+	}
+
+	// the range holds token0 above the current price and token1 below it
+	amount0, amount1 := constants.Zero, constants.Zero
+	switch {
+	case p.SqrtRatioX64.Cmp(sqrtRatioLowerX64) <= 0:
+		amount0 = utils.GetAmount0Delta(sqrtRatioLowerX64, sqrtRatioUpperX64, liquidity, false)
+	case p.SqrtRatioX64.Cmp(sqrtRatioUpperX64) >= 0:
+		amount1 = utils.GetAmount1Delta(sqrtRatioLowerX64, sqrtRatioUpperX64, liquidity, false)
+	default:
+		amount0 = utils.GetAmount0Delta(p.SqrtRatioX64, sqrtRatioUpperX64, liquidity, false)
+		amount1 = utils.GetAmount1Delta(sqrtRatioLowerX64, p.SqrtRatioX64, liquidity, false)
+	}
+
+	return &LiquidityRange{
+		TickLower:  tickLower,
+		TickUpper:  tickUpper,
+		Liquidity:  liquidity,
+		Amount0:    FromRawAmount(p.Token0, amount0),
+		Amount1:    FromRawAmount(p.Token1, amount1),
+		PriceLower: NewPrice(p.Token0, p.Token1, constants.Q128, new(big.Int).Mul(sqrtRatioLowerX64, sqrtRatioLowerX64)),
+		PriceUpper: NewPrice(p.Token0, p.Token1, constants.Q128, new(big.Int).Mul(sqrtRatioUpperX64, sqrtRatioUpperX64)),
+	}, nil
+}
+
+// liquidityRangeJSON holds the raw liquidity and token amounts and the prices adjusted for the token decimals
+type liquidityRangeJSON struct {
+	TickLower  int    `json:"tick_lower"`
+	TickUpper  int    `json:"tick_upper"`
+	Liquidity  string `json:"liquidity"`
+	Amount0    string `json:"amount0"`
+	Amount1    string `json:"amount1"`
+	PriceLower string `json:"price_lower"`
+	PriceUpper string `json:"price_upper"`
+	Active     bool   `json:"active"`
+}
+
+type liquidityProfileJSON struct {
+	Token0      *Token               `json:"token0"`
+	Token1      *Token               `json:"token1"`
+	TickCurrent int                  `json:"tick_current"`
+	Ranges      []liquidityRangeJSON `json:"ranges"`
+}
+
+func (r *LiquidityRange) toJSON() liquidityRangeJSON {
+	return liquidityRangeJSON{
+		TickLower:  r.TickLower,
+		TickUpper:  r.TickUpper,
+		Liquidity:  r.Liquidity.String(),
+		Amount0:    r.Amount0.Quotient().String(),
+		Amount1:    r.Amount1.Quotient().String(),
+		PriceLower: r.PriceLower.ToSignificant(profilePriceDigits),
+		PriceUpper: r.PriceUpper.ToSignificant(profilePriceDigits),
+		Active:     r.Active,
+	}
+}
+
+// MarshalJSON encodes the profile with raw liquidity and token amounts as decimal strings and the prices adjusted
+// for the token decimals
+func (lp *LiquidityProfile) MarshalJSON() ([]byte, error) {
+	ranges := make([]liquidityRangeJSON, 0, len(lp.Ranges))
+	for i := range lp.Ranges {
+		ranges = append(ranges, lp.Ranges[i].toJSON())
+	}
+	return json.Marshal(liquidityProfileJSON{
+		Token0:      lp.Token0,
+		Token1:      lp.Token1,
+		TickCurrent: lp.TickCurrent,
+		Ranges:      ranges,
+	})
+}
+
+/**
+ * Writes the ranges of the profile as CSV with a header row, with the same columns and values as the JSON encoding
+ * @param w the destination of the CSV
+ */
+func (lp *LiquidityProfile) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"tick_lower", "tick_upper", "liquidity", "amount0", "amount1", "price_lower", "price_upper", "active"}); err != nil {
+		return err
+	}
+	for i := range lp.Ranges {
+		r := lp.Ranges[i].toJSON()
+		record := []string{
+			strconv.Itoa(r.TickLower),
+			strconv.Itoa(r.TickUpper),
+			r.Liquidity,
+			r.Amount0,
+			r.Amount1,
+			r.PriceLower,
+			r.PriceUpper,
+			strconv.FormatBool(r.Active),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
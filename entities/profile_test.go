@@ -0,0 +1,134 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// newProfilePool returns a pool with liquidity a on [-120, 120] and b on [-60, 60]
+func newProfilePool(t *testing.T, tickCurrent int, liquidity *big.Int) *Pool {
+	a, b := big.NewInt(3e12), big.NewInt(1e12)
+	ticks := []Tick{
+		{Index: -120, LiquidityNet: a, LiquidityGross: a},
+		{Index: -60, LiquidityNet: b, LiquidityGross: b},
+		{Index: 60, LiquidityNet: new(big.Int).Neg(b), LiquidityGross: b},
+		{Index: 120, LiquidityNet: new(big.Int).Neg(a), LiquidityGross: a},
+	}
+	p, err := NewTickListDataProvider(ticks, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqrtRatioX64, err := utils.GetSqrtRatioAtTick(tickCurrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewPool(USDC, DAI, constants.FeeMedium, 60, sqrtRatioX64, liquidity, tickCurrent, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestLiquidityProfile(t *testing.T) {
+	profile, err := newProfilePool(t, 0, big.NewInt(4e12)).LiquidityProfile()
+	assert.NoError(t, err)
+	if !assert.Len(t, profile.Ranges, 3) {
+		return
+	}
+	for i, want := range []int64{3e12, 4e12, 3e12} {
+		assert.Equal(t, big.NewInt(want), profile.Ranges[i].Liquidity)
+	}
+	assert.Equal(t, []bool{false, true, false}, []bool{profile.Ranges[0].Active, profile.Ranges[1].Active, profile.Ranges[2].Active})
+
+	// ranges below the price hold token1, ranges above it token0 and the active range both
+	assert.Equal(t, 0, profile.Ranges[0].Amount0.Quotient().Sign())
+	assert.Equal(t, 1, profile.Ranges[0].Amount1.Quotient().Sign())
+	assert.Equal(t, 1, profile.Ranges[1].Amount0.Quotient().Sign())
+	assert.Equal(t, 1, profile.Ranges[1].Amount1.Quotient().Sign())
+	assert.Equal(t, 1, profile.Ranges[2].Amount0.Quotient().Sign())
+	assert.Equal(t, 0, profile.Ranges[2].Amount1.Quotient().Sign())
+	assert.Equal(t, profile.Ranges[0].PriceUpper, profile.Ranges[1].PriceLower)
+	assert.True(t, profile.Ranges[1].PriceLower.LessThan(profile.Ranges[1].PriceUpper.Fraction))
+	midPrice := newProfilePool(t, 0, big.NewInt(4e12)).Token0Price()
+	assert.True(t, profile.Ranges[1].PriceLower.LessThan(midPrice.Fraction))
+	assert.True(t, midPrice.LessThan(profile.Ranges[1].PriceUpper.Fraction))
+
+	// the current tick may be outside of the initialized ticks
+	profile, err = newProfilePool(t, -200, big.NewInt(0)).LiquidityProfile()
+	assert.NoError(t, err)
+	for i, want := range []int64{3e12, 4e12, 3e12} {
+		assert.Equal(t, big.NewInt(want), profile.Ranges[i].Liquidity)
+		assert.False(t, profile.Ranges[i].Active)
+		assert.Equal(t, 0, profile.Ranges[i].Amount1.Quotient().Sign())
+	}
+	profile, err = newProfilePool(t, 120, big.NewInt(0)).LiquidityProfile()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3e12), profile.Ranges[0].Liquidity)
+	assert.Equal(t, 0, profile.Ranges[2].Amount0.Quotient().Sign())
+
+	_, err = newProfilePool(t, 0, big.NewInt(0)).LiquidityProfile()
+	assert.ErrorIs(t, err, ErrInconsistentLiquidity)
+}
+
+// unlistedTicks hides the Ticks method of a provider, like providers that load ticks on demand
+type unlistedTicks struct {
+	TickDataProvider
+}
+
+func TestLiquidityProfileWithoutTickLister(t *testing.T) {
+	for _, tt := range []struct {
+		tickCurrent int
+		liquidity   int64
+	}{{-200, 0}, {-100, 3e12}, {-60, 4e12}, {0, 4e12}, {59, 4e12}, {60, 3e12}, {120, 0}, {5000, 0}} {
+		pool := newProfilePool(t, tt.tickCurrent, big.NewInt(tt.liquidity))
+		want, err := pool.LiquidityProfile()
+		if !assert.NoError(t, err, tt.tickCurrent) {
+			continue
+		}
+		assert.Len(t, want.Ranges, 3, tt.tickCurrent)
+		pool.TickDataProvider = unlistedTicks{pool.TickDataProvider}
+		got, err := pool.LiquidityProfile()
+		assert.NoError(t, err, tt.tickCurrent)
+		assert.Equal(t, want, got, tt.tickCurrent)
+	}
+}
+
+func TestLiquidityProfileExport(t *testing.T) {
+	profile, err := newProfilePool(t, 0, big.NewInt(4e12)).LiquidityProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(profile)
+	assert.NoError(t, err)
+	var decoded struct {
+		TickCurrent int `json:"tick_current"`
+		Ranges      []struct {
+			TickLower int    `json:"tick_lower"`
+			Liquidity string `json:"liquidity"`
+			Amount0   string `json:"amount0"`
+			Active    bool   `json:"active"`
+		} `json:"ranges"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Ranges, 3)
+	assert.Equal(t, -60, decoded.Ranges[1].TickLower)
+	assert.Equal(t, "4000000000000", decoded.Ranges[1].Liquidity)
+	assert.Equal(t, profile.Ranges[1].Amount0.Quotient().String(), decoded.Ranges[1].Amount0)
+	assert.True(t, decoded.Ranges[1].Active)
+
+	var buf bytes.Buffer
+	assert.NoError(t, profile.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "tick_lower,tick_upper,liquidity,amount0,amount1,price_lower,price_upper,active", lines[0])
+	assert.True(t, strings.HasPrefix(lines[2], "-60,60,4000000000000,"))
+	assert.True(t, strings.HasSuffix(lines[2], ",true"))
+}
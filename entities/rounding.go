@@ -0,0 +1,44 @@
+package entities
+
+import "math/big"
+
+// Rounding decides how a value between two representable values is rounded
+type Rounding int
+
+const (
+	RoundDown     Rounding = iota // towards zero
+	RoundHalfUp                   // to the nearest, halves away from zero
+	RoundHalfEven                 // to the nearest, halves to the even neighbour
+	RoundUp                       // away from zero
+)
+
+/**
+ * Divides numerator by denominator, rounding the quotient as given
+ * @param numerator the dividend
+ * @param denominator the divisor, not zero
+ * @param rounding how to round an inexact quotient
+ */
+func divRound(numerator, denominator *big.Int, rounding Rounding) *big.Int {
+	q, r := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	// the quotient is truncated, so rounding away from zero moves it by the sign of the exact result
+	away := big.NewInt(int64(numerator.Sign() * denominator.Sign()))
+	switch rounding {
+	case RoundUp:
+		return q.Add(q, away)
+	case RoundHalfUp, RoundHalfEven:
+		twice := new(big.Int).Abs(r)
+		twice.Lsh(twice, 1)
+		switch twice.CmpAbs(denominator) {
+		case 1:
+			return q.Add(q, away)
+		case 0:
+			if rounding == RoundHalfUp || q.Bit(0) == 1 {
+				return q.Add(q, away)
+			}
+		}
+	}
+	return q
+}
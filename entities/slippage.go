@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+var ErrPriceLimitAndTolerance = errors.New("either a sqrt price limit or a slippage tolerance can be given")
+
+/**
+ * Returns the sqrt price limit of a swap of tokenIn that lets the price of tokenIn fall by at most the tolerance,
+ * clamped to the valid limits MinSqrtRatio+1 and MaxSqrtRatio-1. The limit is validated like an explicit one, so a
+ * tolerance that leaves no room to swap, e.g. zero or one that rounds away, or a pool at the clamped bound, fails.
+ * @param tokenIn the input token of the swap
+ * @param slippageTolerance how far the price of tokenIn may fall, from 0 to 100%
+ * @throws ErrSqrtPriceLimitX64TooHigh if the limit is not below the current price of a zero for one swap
+ * @throws ErrSqrtPriceLimitX64TooLow if the limit is not above the current price of a one for zero swap
+ */
+func (p *Pool) SqrtPriceLimitForSlippage(tokenIn *Token, slippageTolerance *Percent) (*big.Int, error) {
+	if !p.InvolvesToken(tokenIn) {
+		return nil, ErrTokenNotInvolved
+	}
+	return p.sqrtPriceLimitForSlippage(tokenIn.Equal(p.Token0), slippageTolerance)
+}
+
+func (p *Pool) sqrtPriceLimitForSlippage(zeroForOne bool, slippageTolerance *Percent) (*big.Int, error) {
+	limit, err := p.sqrtPriceForSlippage(zeroForOne, slippageTolerance)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateSqrtPriceLimit(zeroForOne, limit); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+// sqrtPriceForSlippage returns the sqrt price at which the price of the input token has fallen by the tolerance,
+// rounded towards the current price and clamped to the valid limits, which may leave it at the current price
+func (p *Pool) sqrtPriceForSlippage(zeroForOne bool, slippageTolerance *Percent) (*big.Int, error) {
+	if !validTolerance(slippageTolerance) {
+		return nil, ErrInvalidSlippageTolerance
+	}
+	tolerance := slippageTolerance.Fraction.Reduce()
+	kept := new(big.Int).Sub(tolerance.Denominator, tolerance.Numerator) // (1 - tolerance) * denominator
+
+	// the price of token0 falls to price * (1 - tolerance) for zero for one, and rises to price / (1 - tolerance)
+	// otherwise, so the price of token1 in token0 falls by the tolerance
+	minimum := new(big.Int).Add(utils.MinSqrtRatio, constants.One)
+	maximum := new(big.Int).Sub(utils.MaxSqrtRatio, constants.One)
+	squared := new(big.Int).Mul(p.SqrtRatioX64, p.SqrtRatioX64)
+	var limit *big.Int
+	if zeroForOne {
+		// round up, a floor would let the price fall further than the tolerance
+		squared.Mul(squared, kept)
+		if new(big.Int).Rem(squared, tolerance.Denominator).Sign() != 0 {
+			squared.Quo(squared, tolerance.Denominator).Add(squared, constants.One)
+		} else {
+			squared.Quo(squared, tolerance.Denominator)
+		}
+		limit = new(big.Int).Sqrt(squared)
+		if new(big.Int).Mul(limit, limit).Cmp(squared) < 0 {
+			limit.Add(limit, constants.One)
+		}
+	} else {
+		if kept.Sign() == 0 {
+			return maximum, nil
+		}
+		limit = new(big.Int).Sqrt(squared.Mul(squared, tolerance.Denominator).Quo(squared, kept))
+	}
+	if limit.Cmp(minimum) < 0 {
+		return minimum, nil
+	}
+	if limit.Cmp(maximum) > 0 {
+		return maximum, nil
+	}
+	return limit, nil
+}
+
+// validTolerance returns whether the tolerance is between 0 and 100%. A zero denominator compares equal to anything,
+// so it is rejected first.
+func validTolerance(tolerance *Percent) bool {
+	return tolerance.Denominator.Sign() != 0 &&
+		tolerance.Fraction.Cmp(NewFraction(big.NewInt(0), big.NewInt(1))) >= 0 &&
+		tolerance.Fraction.Cmp(NewFraction(big.NewInt(1), big.NewInt(1))) <= 0
+}
@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqrtPriceLimitForSlippage(t *testing.T) {
+	pool := newTestPool()
+	onePercent := NewPercent(big.NewInt(1), big.NewInt(100))
+
+	// the price of the input token falls by the tolerance in either direction
+	limit, err := pool.SqrtPriceLimitForSlippage(pool.Token0, onePercent)
+	assert.NoError(t, err)
+	price := NewFraction(new(big.Int).Mul(limit, limit), new(big.Int).Mul(pool.SqrtRatioX64, pool.SqrtRatioX64))
+	assert.Equal(t, "0.9900", price.ToFixed(4))
+	limit, err = pool.SqrtPriceLimitForSlippage(pool.Token1, onePercent)
+	assert.NoError(t, err)
+	price = NewFraction(new(big.Int).Mul(pool.SqrtRatioX64, pool.SqrtRatioX64), new(big.Int).Mul(limit, limit))
+	assert.Equal(t, "0.9900", price.ToFixed(4))
+
+	// the limits are clamped to the valid range
+	all := NewPercent(big.NewInt(1), big.NewInt(1))
+	limit, err = pool.SqrtPriceLimitForSlippage(pool.Token0, all)
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Add(utils.MinSqrtRatio, constants.One), limit)
+	limit, err = pool.SqrtPriceLimitForSlippage(pool.Token1, all)
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Sub(utils.MaxSqrtRatio, constants.One), limit)
+
+	// no room to swap: a zero tolerance, or a pool already at the clamped bound
+	none := NewPercent(big.NewInt(0), big.NewInt(1))
+	_, err = pool.SqrtPriceLimitForSlippage(pool.Token0, none)
+	assert.ErrorIs(t, err, ErrSqrtPriceLimitX64TooHigh)
+	_, err = pool.SqrtPriceLimitForSlippage(pool.Token1, none)
+	assert.ErrorIs(t, err, ErrSqrtPriceLimitX64TooLow)
+	high := *pool
+	high.SqrtRatioX64 = new(big.Int).Sub(utils.MaxSqrtRatio, constants.One)
+	_, err = high.SqrtPriceLimitForSlippage(pool.Token1, onePercent)
+	assert.ErrorIs(t, err, ErrSqrtPriceLimitX64TooLow)
+	low := *pool
+	low.SqrtRatioX64 = new(big.Int).Add(utils.MinSqrtRatio, constants.One)
+	_, err = low.SqrtPriceLimitForSlippage(pool.Token0, onePercent)
+	assert.ErrorIs(t, err, ErrSqrtPriceLimitX64TooHigh)
+
+	_, err = pool.SqrtPriceLimitForSlippage(pool.Token0, NewPercent(big.NewInt(101), big.NewInt(100)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+	_, err = pool.SqrtPriceLimitForSlippage(pool.Token0, NewPercent(big.NewInt(-1), big.NewInt(100)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+	_, err = pool.SqrtPriceLimitForSlippage(pool.Token0, NewPercent(big.NewInt(0), big.NewInt(0)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+	_, err = pool.GetOutputAmount(FromRawAmount(pool.Token0, big.NewInt(1e12)), nil, WithSlippageTolerance(NewPercent(big.NewInt(0), big.NewInt(0))))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+	_, err = pool.Depth(pool.Token1, NewPercent(big.NewInt(0), big.NewInt(0)))
+	assert.ErrorIs(t, err, ErrInvalidSlippageTolerance)
+	_, err = pool.SqrtPriceLimitForSlippage(WETH9[1], onePercent)
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
+}
+
+func TestSqrtPriceLimitForSlippageRounding(t *testing.T) {
+	pool := newTestPool()
+	for _, tolerance := range []*Percent{
+		NewPercent(big.NewInt(1), big.NewInt(100)),
+		NewPercent(big.NewInt(1), big.NewInt(3)),
+		NewPercent(big.NewInt(7), big.NewInt(10000)),
+	} {
+		// the limit is the smallest sqrt price whose price is at least price * (1 - tolerance)
+		limit, err := pool.SqrtPriceLimitForSlippage(pool.Token0, tolerance)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		kept := NewFraction(big.NewInt(1), big.NewInt(1)).Subtract(tolerance.Fraction)
+		floor := new(big.Int).Mul(pool.SqrtRatioX64, pool.SqrtRatioX64)
+		floor.Mul(floor, kept.Numerator)
+		below := new(big.Int).Sub(limit, constants.One)
+		assert.True(t, new(big.Int).Mul(new(big.Int).Mul(limit, limit), kept.Denominator).Cmp(floor) >= 0, tolerance.ToFixed(4))
+		assert.True(t, new(big.Int).Mul(new(big.Int).Mul(below, below), kept.Denominator).Cmp(floor) < 0, tolerance.ToFixed(4))
+	}
+}
+
+func TestGetOutputAmountWithSlippageTolerance(t *testing.T) {
+	pool := newTestPool()
+	onePercent := NewPercent(big.NewInt(1), big.NewInt(100))
+	limit, err := pool.SqrtPriceLimitForSlippage(pool.Token0, onePercent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a large swap stops where the price has moved by the tolerance
+	input := FromRawAmount(pool.Token0, big.NewInt(1e17))
+	result, err := pool.GetOutputAmount(input, nil, WithSlippageTolerance(onePercent))
+	assert.NoError(t, err)
+	assert.True(t, result.PartiallyFilled())
+	assert.Equal(t, limit, result.SqrtRatioX64)
+
+	// a small one is not affected
+	small := FromRawAmount(pool.Token0, big.NewInt(1e12))
+	result, err = pool.GetOutputAmount(small, nil, WithSlippageTolerance(onePercent))
+	assert.NoError(t, err)
+	assert.False(t, result.PartiallyFilled())
+
+	_, err = pool.GetOutputAmount(input, limit, WithSlippageTolerance(onePercent))
+	assert.ErrorIs(t, err, ErrPriceLimitAndTolerance)
+	_, err = pool.GetInputAmount(FromRawAmount(pool.Token1, big.NewInt(1e17)), nil, WithSlippageTolerance(onePercent))
+	assert.NoError(t, err)
+}
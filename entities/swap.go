@@ -0,0 +1,135 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+)
+
+var ErrPartialFill = errors.New("swap partially filled")
+
+// PartialFillError is returned when a swap quoted WithRequireFullFill stops at the sqrt price limit before the
+// specified amount is filled. It matches ErrPartialFill with errors.Is.
+type PartialFillError struct {
+	ExactInput      bool            // whether the specified amount was the input (true) or the output (false)
+	AmountIn        *CurrencyAmount // the input that would be consumed, including fees
+	AmountOut       *CurrencyAmount // the output that would be received
+	AmountRemaining *CurrencyAmount // the unfilled part of the specified amount
+}
+
+func (e *PartialFillError) Error() string {
+	if e.ExactInput {
+		return fmt.Sprintf("%s: %s %s of input left unfilled", ErrPartialFill, e.AmountRemaining.Quotient(), e.AmountRemaining.Currency.Symbol())
+	}
+	return fmt.Sprintf("%s: %s %s of output left unfilled", ErrPartialFill, e.AmountRemaining.Quotient(), e.AmountRemaining.Currency.Symbol())
+}
+
+func (e *PartialFillError) Is(target error) bool {
+	return target == ErrPartialFill
+}
+
+// SwapResult is the outcome of a quoted swap
+type SwapResult struct {
+	AmountIn          *CurrencyAmount    // the amount of the input token swapped in, including fees
+	AmountOut         *CurrencyAmount    // the amount of the output token swapped out
+	FeeAmount         *CurrencyAmount    // the total fee paid in the input token
+	ProtocolFee       *CurrencyAmount    // the part of FeeAmount taken by the protocol
+	CrossedTicks      int                // the number of initialized ticks crossed
+	SqrtRatioX64      *big.Int           // the sqrt price after the swap
+	TickCurrent       int                // the tick after the swap
+	Liquidity         *big.Int           // the in range liquidity after the swap
+	PriceLimitReached bool               // whether the swap stopped at the sqrt price limit
+	AmountRemaining   *CurrencyAmount    // the unfilled part of the specified amount, in the specified token
+	Steps             []StepComputations // the steps of the swap, only recorded with WithTrace
+	Pool              *Pool              // the pool with state updated after the swap
+}
+
+// SwapOption configures how a swap is quoted
+type SwapOption func(*swapOptions)
+
+type swapOptions struct {
+	trace             bool
+	requireFullFill   bool
+	slippageTolerance *Percent
+}
+
+func newSwapOptions(opts []SwapOption) *swapOptions {
+	o := &swapOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithTrace records every step of the swap in SwapResult.Steps
+func WithTrace() SwapOption {
+	return func(o *swapOptions) {
+		o.trace = true
+	}
+}
+
+// WithRequireFullFill fails the quote with a *PartialFillError instead of returning a partially filled result when
+// the sqrt price limit is reached before the specified amount is filled
+func WithRequireFullFill() SwapOption {
+	return func(o *swapOptions) {
+		o.requireFullFill = true
+	}
+}
+
+// WithSlippageTolerance derives the sqrt price limit from a tolerance instead of an explicit limit, see
+// Pool.SqrtPriceLimitForSlippage. The swap then stops where the price has moved by the tolerance, and quoting with
+// an explicit limit as well fails with ErrPriceLimitAndTolerance, as does GetInputAmountToSqrtPrice, whose target is
+// the limit.
+func WithSlippageTolerance(slippageTolerance *Percent) SwapOption {
+	return func(o *swapOptions) {
+		o.slippageTolerance = slippageTolerance
+	}
+}
+
+// PartiallyFilled returns whether the swap stopped at the price limit before the specified amount was filled. The
+// consumed input is AmountIn, the received output is AmountOut and the unfilled part is AmountRemaining.
+func (r *SwapResult) PartiallyFilled() bool {
+	return r.AmountRemaining.Quotient().Cmp(constants.Zero) != 0
+}
+
+// newSwapResult converts the final state of a swap into a SwapResult
+func (p *Pool) newSwapResult(zeroForOne, exactInput bool, state *swapState, opts *swapOptions) (*SwapResult, error) {
+	inputToken, outputToken := p.Token1, p.Token0
+	if zeroForOne {
+		inputToken, outputToken = p.Token0, p.Token1
+	}
+	specifiedToken := inputToken
+	if !exactInput {
+		specifiedToken = outputToken
+	}
+
+	pool, err := p.withState(state.sqrtPriceX64, state.liquidity, state.tick)
+	if err != nil {
+		return nil, err
+	}
+	result := &SwapResult{
+		AmountIn:          FromRawAmount(inputToken, state.amountIn),
+		AmountOut:         FromRawAmount(outputToken, state.amountOut),
+		FeeAmount:         FromRawAmount(inputToken, state.feeAmount),
+		ProtocolFee:       FromRawAmount(inputToken, state.protocolFee),
+		CrossedTicks:      state.crossedTicks,
+		SqrtRatioX64:      state.sqrtPriceX64,
+		TickCurrent:       state.tick,
+		Liquidity:         state.liquidity,
+		PriceLimitReached: state.sqrtPriceX64.Cmp(state.sqrtPriceLimitX64) == 0,
+		AmountRemaining:   FromRawAmount(specifiedToken, new(big.Int).Abs(state.amountSpecifiedRemaining)),
+		Steps:             state.steps,
+		Pool:              pool,
+	}
+	if opts.requireFullFill && result.PartiallyFilled() {
+		return nil, &PartialFillError{
+			ExactInput:      exactInput,
+			AmountIn:        result.AmountIn,
+			AmountOut:       result.AmountOut,
+			AmountRemaining: result.AmountRemaining,
+		}
+	}
+	return result, nil
+}
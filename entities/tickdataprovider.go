@@ -13,14 +13,26 @@ type TickDataProvider interface {
 	/**
 	 * Return information corresponding to a specific tick
 	 * @param tick the tick to load
+	 * @throws if the tick is not initialized or cannot be loaded
 	 */
-	GetTick(tick int) Tick
+	GetTick(tick int) (Tick, error)
 
 	/**
 	 * Return the next tick that is initialized within a single word
 	 * @param tick The current tick
 	 * @param lte Whether the next tick should be lte the current tick
 	 * @param tickSpacing The tick spacing of the pool
+	 * @throws if the ticks cannot be loaded; no initialized ticks is not an error
 	 */
-	NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool)
+	NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool, error)
+}
+
+// A tick data provider that can also list all of its initialized ticks, e.g. TickListDataProvider
+type TickLister interface {
+	TickDataProvider
+
+	/**
+	 * Return all initialized ticks, sorted by index
+	 */
+	Ticks() []Tick
 }
@@ -11,6 +11,10 @@ var (
 	ErrInvalidTickSpacing = errors.New("invalid tick spacing")
 	ErrZeroNet            = errors.New("tick net delta must be zero")
 	ErrSorted             = errors.New("ticks must be sorted")
+	ErrEmptyTickList      = errors.New("empty tick list")
+	ErrBelowSmallest      = errors.New("tick is below the smallest tick")
+	ErrAtOrAboveLargest   = errors.New("tick is at or above the largest tick")
+	ErrTickNotFound       = errors.New("tick is not contained in ticks")
 )
 
 func ValidateList(ticks []Tick, tickSpacing int) error {
@@ -41,72 +45,190 @@ func ValidateList(ticks []Tick, tickSpacing int) error {
 	return nil
 }
 
-func IsBelowSmallest(ticks []Tick, tick int) bool {
+/**
+ * Returns whether tick is below the smallest initialized tick
+ * @param ticks sorted list of ticks
+ * @param tick the tick to compare
+ * @throws ErrEmptyTickList if the list is empty
+ */
+func IsBelowSmallest(ticks []Tick, tick int) (bool, error) {
 	if len(ticks) == 0 {
-		panic("empty tick list")
+		return false, ErrEmptyTickList
 	}
-	return tick < ticks[0].Index
+	return tick < ticks[0].Index, nil
 }
 
-func IsAtOrAboveLargest(ticks []Tick, tick int) bool {
+/**
+ * Returns whether tick is at or above the largest initialized tick
+ * @param ticks sorted list of ticks
+ * @param tick the tick to compare
+ * @throws ErrEmptyTickList if the list is empty
+ */
+func IsAtOrAboveLargest(ticks []Tick, tick int) (bool, error) {
 	if len(ticks) == 0 {
-		panic("empty tick list")
+		return false, ErrEmptyTickList
 	}
-	return tick >= ticks[len(ticks)-1].Index
+	return tick >= ticks[len(ticks)-1].Index, nil
 }
 
-func GetTick(ticks []Tick, index int) Tick {
-	tick := ticks[binarySearch(ticks, index)]
+/**
+ * Returns the initialized tick with the given index
+ * @param ticks sorted list of ticks
+ * @param index the index of the tick
+ * @throws ErrEmptyTickList if the list is empty
+ * @throws ErrTickNotFound if the tick is not initialized
+ */
+func GetTick(ticks []Tick, index int) (Tick, error) {
+	i, err := binarySearch(ticks, index)
+	if errors.Is(err, ErrBelowSmallest) {
+		return Tick{}, ErrTickNotFound
+	}
+	if err != nil {
+		return Tick{}, err
+	}
+	tick := ticks[i]
 	if tick.Index != index {
-		panic("index is not contained in ticks")
+		return Tick{}, ErrTickNotFound
 	}
-	return tick
+	return tick, nil
 }
 
-func NextInitializedTick(ticks []Tick, tick int, lte bool) Tick {
+/**
+ * Returns the next initialized tick at or below tick if lte, or above tick otherwise
+ * @param ticks sorted list of ticks
+ * @param tick the tick to start from
+ * @param lte whether to search at or below tick
+ * @throws ErrEmptyTickList if the list is empty
+ * @throws ErrBelowSmallest if lte and tick is below the smallest tick
+ * @throws ErrAtOrAboveLargest if not lte and tick is at or above the largest tick
+ */
+func NextInitializedTick(ticks []Tick, tick int, lte bool) (Tick, error) {
+	below, err := IsBelowSmallest(ticks, tick)
+	if err != nil {
+		return Tick{}, err
+	}
+	atOrAbove, _ := IsAtOrAboveLargest(ticks, tick)
 	if lte {
-		if IsBelowSmallest(ticks, tick) {
-			panic("below smallest")
+		if below {
+			return Tick{}, ErrBelowSmallest
+		}
+		if atOrAbove {
+			return ticks[len(ticks)-1], nil
 		}
-		if IsAtOrAboveLargest(ticks, tick) {
-			return ticks[len(ticks)-1]
+		index, err := binarySearch(ticks, tick)
+		if err != nil {
+			return Tick{}, err
 		}
-		index := binarySearch(ticks, tick)
-		return ticks[index]
+		return ticks[index], nil
 	} else {
-		if IsAtOrAboveLargest(ticks, tick) {
-			panic("at or above largest")
+		if atOrAbove {
+			return Tick{}, ErrAtOrAboveLargest
 		}
-		if IsBelowSmallest(ticks, tick) {
-			return ticks[0]
+		if below {
+			return ticks[0], nil
 		}
-		index := binarySearch(ticks, tick)
-		return ticks[index+1]
+		index, err := binarySearch(ticks, tick)
+		if err != nil {
+			return Tick{}, err
+		}
+		return ticks[index+1], nil
 	}
 }
 
-func NextInitializedTickWithinOneWord(ticks []Tick, tick int, lte bool, tickSpacing int) (int, bool) {
+/**
+ * Returns the next initialized tick within the word of tick, or the word boundary if there is none. An empty list
+ * has no initialized ticks, so the result is always the word boundary.
+ * @param ticks sorted list of ticks
+ * @param tick the tick to start from
+ * @param lte whether to search at or below tick
+ * @param tickSpacing the tick spacing of the pool
+ */
+func NextInitializedTickWithinOneWord(ticks []Tick, tick int, lte bool, tickSpacing int) (int, bool, error) {
+	if tickSpacing <= 0 {
+		return 0, false, ErrZeroTickSpacing
+	}
 	compressed := math.Floor(float64(tick) / float64(tickSpacing)) // matches rounding in the code
 
 	if lte {
 		wordPos := int(compressed) >> 8
 		minimum := (wordPos << 8) * tickSpacing
-		if IsBelowSmallest(ticks, tick) {
-			return minimum, false
+		if below, _ := IsBelowSmallest(ticks, tick); below || len(ticks) == 0 {
+			return minimum, false, nil
+		}
+		next, err := NextInitializedTick(ticks, tick, lte)
+		if err != nil {
+			return 0, false, err
 		}
-		index := NextInitializedTick(ticks, tick, lte).Index
-		nextInitializedTick := math.Max(float64(minimum), float64(index))
-		return int(nextInitializedTick), int(nextInitializedTick) == index
+		nextInitializedTick := math.Max(float64(minimum), float64(next.Index))
+		return int(nextInitializedTick), int(nextInitializedTick) == next.Index, nil
 	} else {
 		wordPos := int(compressed+1) >> 8
 		maximum := ((wordPos+1)<<8)*tickSpacing - 1
-		if IsAtOrAboveLargest(ticks, tick) {
-			return maximum, false
+		if atOrAbove, _ := IsAtOrAboveLargest(ticks, tick); atOrAbove || len(ticks) == 0 {
+			return maximum, false, nil
 		}
-		index := NextInitializedTick(ticks, tick, lte).Index
-		nextInitializedTick := math.Min(float64(maximum), float64(index))
-		return int(nextInitializedTick), int(nextInitializedTick) == index
+		next, err := NextInitializedTick(ticks, tick, lte)
+		if err != nil {
+			return 0, false, err
+		}
+		nextInitializedTick := math.Min(float64(maximum), float64(next.Index))
+		return int(nextInitializedTick), int(nextInitializedTick) == next.Index, nil
+	}
+}
+
+// MustIsBelowSmallest is like IsBelowSmallest but panics on error, e.g. on an empty list.
+//
+// Deprecated: use IsBelowSmallest.
+func MustIsBelowSmallest(ticks []Tick, tick int) bool {
+	below, err := IsBelowSmallest(ticks, tick)
+	if err != nil {
+		panic(err)
+	}
+	return below
+}
+
+// MustIsAtOrAboveLargest is like IsAtOrAboveLargest but panics on error, e.g. on an empty list.
+//
+// Deprecated: use IsAtOrAboveLargest.
+func MustIsAtOrAboveLargest(ticks []Tick, tick int) bool {
+	atOrAbove, err := IsAtOrAboveLargest(ticks, tick)
+	if err != nil {
+		panic(err)
+	}
+	return atOrAbove
+}
+
+// MustGetTick is like GetTick but panics on error.
+//
+// Deprecated: use GetTick.
+func MustGetTick(ticks []Tick, index int) Tick {
+	tick, err := GetTick(ticks, index)
+	if err != nil {
+		panic(err)
+	}
+	return tick
+}
+
+// MustNextInitializedTick is like NextInitializedTick but panics on error.
+//
+// Deprecated: use NextInitializedTick.
+func MustNextInitializedTick(ticks []Tick, tick int, lte bool) Tick {
+	next, err := NextInitializedTick(ticks, tick, lte)
+	if err != nil {
+		panic(err)
 	}
+	return next
+}
+
+// MustNextInitializedTickWithinOneWord is like NextInitializedTickWithinOneWord but panics on error.
+//
+// Deprecated: use NextInitializedTickWithinOneWord.
+func MustNextInitializedTickWithinOneWord(ticks []Tick, tick int, lte bool, tickSpacing int) (int, bool) {
+	next, initialized, err := NextInitializedTickWithinOneWord(ticks, tick, lte, tickSpacing)
+	if err != nil {
+		panic(err)
+	}
+	return next, initialized
 }
 
 // utils
@@ -124,11 +246,17 @@ func isTicksSorted(ticks []Tick) bool {
  * Finds the largest tick in the list of ticks that is less than or equal to tick
  * @param ticks list of ticks
  * @param tick tick to find the largest tick that is less than or equal to tick
+ * @throws ErrEmptyTickList if the list is empty
+ * @throws ErrBelowSmallest if tick is below the smallest tick
  * @private
  */
-func binarySearch(ticks []Tick, tick int) int {
-	if IsBelowSmallest(ticks, tick) {
-		panic("tick is below smallest tick")
+func binarySearch(ticks []Tick, tick int) (int, error) {
+	below, err := IsBelowSmallest(ticks, tick)
+	if err != nil {
+		return 0, err
+	}
+	if below {
+		return 0, ErrBelowSmallest
 	}
 
 	// binary search
@@ -137,7 +265,7 @@ func binarySearch(ticks []Tick, tick int) int {
 	for start <= end {
 		mid := (start + end) / 2
 		if ticks[mid].Index == tick {
-			return mid
+			return mid, nil
 		} else if ticks[mid].Index < tick {
 			start = mid + 1
 		} else {
@@ -147,9 +275,9 @@ func binarySearch(ticks []Tick, tick int) int {
 
 	// if we get here, we didn't find a tick that is less than or equal to tick
 	// so we return the index of the tick that is closest to tick
-	if ticks[start].Index < tick {
-		return start
+	if start < len(ticks) && ticks[start].Index < tick {
+		return start, nil
 	} else {
-		return start - 1
+		return start - 1, nil
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
 	"github.com/mythril-labs/clmm-sui-sdk/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,14 +35,45 @@ func TestValidateList(t *testing.T) {
 
 func TestIsBelowSmallest(t *testing.T) {
 	result := []Tick{lowTick, midTick, highTick}
-	assert.True(t, IsBelowSmallest(result, utils.MinTick))
-	assert.False(t, IsBelowSmallest(result, utils.MinTick+1))
+	below, err := IsBelowSmallest(result, utils.MinTick)
+	assert.NoError(t, err)
+	assert.True(t, below)
+	below, err = IsBelowSmallest(result, utils.MinTick+1)
+	assert.NoError(t, err)
+	assert.False(t, below)
+	_, err = IsBelowSmallest(nil, 0)
+	assert.ErrorIs(t, err, ErrEmptyTickList)
+	assert.Panics(t, func() { MustIsBelowSmallest(nil, 0) })
 }
 
 func TestIsAtOrAboveSmallest(t *testing.T) {
 	result := []Tick{lowTick, midTick, highTick}
-	assert.False(t, IsAtOrAboveLargest(result, utils.MaxTick-2))
-	assert.True(t, IsAtOrAboveLargest(result, utils.MaxTick-1))
+	atOrAbove, err := IsAtOrAboveLargest(result, utils.MaxTick-2)
+	assert.NoError(t, err)
+	assert.False(t, atOrAbove)
+	atOrAbove, err = IsAtOrAboveLargest(result, utils.MaxTick-1)
+	assert.NoError(t, err)
+	assert.True(t, atOrAbove)
+	_, err = IsAtOrAboveLargest(nil, 0)
+	assert.ErrorIs(t, err, ErrEmptyTickList)
+	assert.Panics(t, func() { MustIsAtOrAboveLargest(nil, 0) })
+}
+
+func TestGetTick(t *testing.T) {
+	ticks := []Tick{lowTick, midTick, highTick}
+	for _, want := range ticks {
+		tick, err := GetTick(ticks, want.Index)
+		assert.NoError(t, err)
+		assert.Equal(t, want, tick)
+	}
+	for _, index := range []int{utils.MinTick, 1, utils.MaxTick} {
+		_, err := GetTick(ticks, index)
+		assert.ErrorIs(t, err, ErrTickNotFound, "index %d", index)
+	}
+	_, err := GetTick(nil, 0)
+	assert.ErrorIs(t, err, ErrEmptyTickList)
+	assert.Equal(t, midTick, MustGetTick(ticks, 0))
+	assert.Panics(t, func() { MustGetTick(ticks, 1) })
 }
 
 func TestNextInitializedTick(t *testing.T) {
@@ -73,12 +105,19 @@ func TestNextInitializedTick(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.want, NextInitializedTick(tt.args.ticks, tt.args.tick, tt.args.lte))
+			tick, err := NextInitializedTick(tt.args.ticks, tt.args.tick, tt.args.lte)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tick)
 		})
 	}
 
-	assert.Panics(t, func() { NextInitializedTick(ticks, utils.MinTick, true) }, "blow smallest")
-	assert.Panics(t, func() { NextInitializedTick(ticks, utils.MaxTick-1, false) }, "at or above largest")
+	_, err := NextInitializedTick(ticks, utils.MinTick, true)
+	assert.ErrorIs(t, err, ErrBelowSmallest)
+	_, err = NextInitializedTick(ticks, utils.MaxTick-1, false)
+	assert.ErrorIs(t, err, ErrAtOrAboveLargest)
+	_, err = NextInitializedTick(nil, 0, true)
+	assert.ErrorIs(t, err, ErrEmptyTickList)
+	assert.Panics(t, func() { MustNextInitializedTick(ticks, utils.MinTick, true) }, "below smallest")
 }
 
 func TestNextInitializedTickWithinOneWord(t *testing.T) {
@@ -122,10 +161,41 @@ func TestNextInitializedTickWithinOneWord(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got0, got1 := NextInitializedTickWithinOneWord(tt.args.ticks, tt.args.tick, tt.args.lte, tt.args.tickSpacing)
+			got0, got1, err := NextInitializedTickWithinOneWord(tt.args.ticks, tt.args.tick, tt.args.lte, tt.args.tickSpacing)
+			assert.NoError(t, err)
 			assert.Equal(t, tt.want0, got0)
 			assert.Equal(t, tt.want1, got1)
 		})
 	}
 
+	// an empty list has no initialized ticks
+	next, initialized, err := NextInitializedTickWithinOneWord(nil, 1, true, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, next)
+	assert.False(t, initialized)
+	next, initialized, err = NextInitializedTickWithinOneWord(nil, 1, false, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 255, next)
+	assert.False(t, initialized)
+
+	_, _, err = NextInitializedTickWithinOneWord(ticks, 0, true, 0)
+	assert.ErrorIs(t, err, ErrZeroTickSpacing)
+	assert.Panics(t, func() { MustNextInitializedTickWithinOneWord(ticks, 0, true, 0) })
+}
+
+func TestSwapWithEmptyTickList(t *testing.T) {
+	p, err := NewTickListDataProvider(nil, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewPool(USDC, DAI, constants.FeeMedium, 60, constants.Q64, big.NewInt(0), 0, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := pool.GetOutputAmount(FromRawAmount(USDC, big.NewInt(100)), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.AmountOut.Quotient().Sign())
+	assert.True(t, result.PartiallyFilled())
+	_, err = pool.GetOutputAmount(FromRawAmount(USDC, big.NewInt(100)), nil, WithRequireFullFill())
+	assert.ErrorIs(t, err, ErrPartialFill)
 }
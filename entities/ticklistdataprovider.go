@@ -12,10 +12,15 @@ func NewTickListDataProvider(ticks []Tick, tickSpacing int) (*TickListDataProvid
 	return &TickListDataProvider{ticks: ticks}, nil
 }
 
-func (p *TickListDataProvider) GetTick(tick int) Tick {
+func (p *TickListDataProvider) GetTick(tick int) (Tick, error) {
 	return GetTick(p.ticks, tick)
 }
 
-func (p *TickListDataProvider) NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool) {
+func (p *TickListDataProvider) NextInitializedTickWithinOneWord(tick int, lte bool, tickSpacing int) (int, bool, error) {
 	return NextInitializedTickWithinOneWord(p.ticks, tick, lte, tickSpacing)
 }
+
+// Ticks returns a copy of the list of ticks, sorted by index
+func (p *TickListDataProvider) Ticks() []Tick {
+	return append([]Tick(nil), p.ticks...)
+}
@@ -0,0 +1,251 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrUnknownEvent = errors.New("unknown event type")
+	ErrInvalidValue = errors.New("invalid event value")
+)
+
+// Event is an event emitted by a CLMM pool
+type Event interface {
+	// PoolID returns the object ID of the pool that emitted the event
+	PoolID() string
+}
+
+// SwapEvent is emitted by every swap in a pool. Coin A is the pool's token0 and coin B its token1.
+type SwapEvent struct {
+	Pool            string
+	Partner         string
+	AToB            bool     // whether coin A was swapped for coin B, i.e. zero for one
+	AmountIn        *big.Int // the input amount, including fees
+	AmountOut       *big.Int
+	RefAmount       *big.Int // the part of the fee paid to the partner
+	FeeAmount       *big.Int
+	VaultAAmount    *big.Int // the pool's balance of coin A after the swap
+	VaultBAmount    *big.Int // the pool's balance of coin B after the swap
+	BeforeSqrtPrice *big.Int
+	AfterSqrtPrice  *big.Int
+	Steps           uint64
+}
+
+// AddLiquidityEvent is emitted when liquidity is added to a position
+type AddLiquidityEvent struct {
+	Pool           string
+	Position       string
+	TickLower      int
+	TickUpper      int
+	Liquidity      *big.Int // the liquidity added
+	AfterLiquidity *big.Int // the liquidity of the position afterwards
+	AmountA        *big.Int
+	AmountB        *big.Int
+}
+
+// RemoveLiquidityEvent is emitted when liquidity is removed from a position
+type RemoveLiquidityEvent struct {
+	Pool           string
+	Position       string
+	TickLower      int
+	TickUpper      int
+	Liquidity      *big.Int // the liquidity removed
+	AfterLiquidity *big.Int // the liquidity of the position afterwards
+	AmountA        *big.Int
+	AmountB        *big.Int
+}
+
+func (e *SwapEvent) PoolID() string            { return e.Pool }
+func (e *AddLiquidityEvent) PoolID() string    { return e.Pool }
+func (e *RemoveLiquidityEvent) PoolID() string { return e.Pool }
+
+// envelope is an event as returned by the Sui JSON-RPC API
+type envelope struct {
+	Type       string          `json:"type"`
+	ParsedJSON json.RawMessage `json:"parsedJson"`
+}
+
+/**
+ * Decodes an event as returned by the Sui JSON-RPC API, i.e. an object with its Move type and parsed JSON content
+ * @param data the JSON encoded event
+ */
+func ParseEvent(data []byte) (Event, error) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return DecodeEvent(e.Type, e.ParsedJSON)
+}
+
+/**
+ * Decodes the parsed JSON content of an event of the given Move type
+ * @param eventType the Move type of the event, e.g. 0x...::pool::SwapEvent
+ * @param parsedJSON the JSON content of the event
+ */
+func DecodeEvent(eventType string, parsedJSON []byte) (Event, error) {
+	var event Event
+	switch {
+	case strings.HasSuffix(eventType, "::pool::SwapEvent"):
+		event = &SwapEvent{}
+	case strings.HasSuffix(eventType, "::pool::AddLiquidityEvent"):
+		event = &AddLiquidityEvent{}
+	case strings.HasSuffix(eventType, "::pool::RemoveLiquidityEvent"):
+		event = &RemoveLiquidityEvent{}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, eventType)
+	}
+	if err := json.Unmarshal(parsedJSON, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// u64 and u128 values are encoded as decimal strings, i32 values as their two's complement bits
+type (
+	jsonUint string
+	jsonI32  struct {
+		Bits uint32 `json:"bits"`
+	}
+)
+
+func (v jsonUint) big(field string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(string(v), 10)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s %q", ErrInvalidValue, field, string(v))
+	}
+	return n, nil
+}
+
+func (v jsonI32) int() int {
+	return int(int32(v.Bits))
+}
+
+func (e *SwapEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Pool            string   `json:"pool"`
+		Partner         string   `json:"partner"`
+		AToB            bool     `json:"atob"`
+		AmountIn        jsonUint `json:"amount_in"`
+		AmountOut       jsonUint `json:"amount_out"`
+		RefAmount       jsonUint `json:"ref_amount"`
+		FeeAmount       jsonUint `json:"fee_amount"`
+		VaultAAmount    jsonUint `json:"vault_a_amount"`
+		VaultBAmount    jsonUint `json:"vault_b_amount"`
+		BeforeSqrtPrice jsonUint `json:"before_sqrt_price"`
+		AfterSqrtPrice  jsonUint `json:"after_sqrt_price"`
+		Steps           jsonUint `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	values, err := parseUints(map[string]jsonUint{
+		"amount_in":         raw.AmountIn,
+		"amount_out":        raw.AmountOut,
+		"ref_amount":        raw.RefAmount,
+		"fee_amount":        raw.FeeAmount,
+		"vault_a_amount":    raw.VaultAAmount,
+		"vault_b_amount":    raw.VaultBAmount,
+		"before_sqrt_price": raw.BeforeSqrtPrice,
+		"after_sqrt_price":  raw.AfterSqrtPrice,
+		"steps":             raw.Steps,
+	})
+	if err != nil {
+		return err
+	}
+	*e = SwapEvent{
+		Pool:            raw.Pool,
+		Partner:         raw.Partner,
+		AToB:            raw.AToB,
+		AmountIn:        values["amount_in"],
+		AmountOut:       values["amount_out"],
+		RefAmount:       values["ref_amount"],
+		FeeAmount:       values["fee_amount"],
+		VaultAAmount:    values["vault_a_amount"],
+		VaultBAmount:    values["vault_b_amount"],
+		BeforeSqrtPrice: values["before_sqrt_price"],
+		AfterSqrtPrice:  values["after_sqrt_price"],
+		Steps:           values["steps"].Uint64(),
+	}
+	return nil
+}
+
+// liquidityEvent is the JSON layout shared by the add and remove liquidity events
+type liquidityEvent struct {
+	Pool           string   `json:"pool"`
+	Position       string   `json:"position"`
+	TickLower      jsonI32  `json:"tick_lower"`
+	TickUpper      jsonI32  `json:"tick_upper"`
+	Liquidity      jsonUint `json:"liquidity"`
+	AfterLiquidity jsonUint `json:"after_liquidity"`
+	AmountA        jsonUint `json:"amount_a"`
+	AmountB        jsonUint `json:"amount_b"`
+}
+
+func (raw *liquidityEvent) values() (map[string]*big.Int, error) {
+	return parseUints(map[string]jsonUint{
+		"liquidity":       raw.Liquidity,
+		"after_liquidity": raw.AfterLiquidity,
+		"amount_a":        raw.AmountA,
+		"amount_b":        raw.AmountB,
+	})
+}
+
+func (e *AddLiquidityEvent) UnmarshalJSON(data []byte) error {
+	var raw liquidityEvent
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	values, err := raw.values()
+	if err != nil {
+		return err
+	}
+	*e = AddLiquidityEvent{
+		Pool:           raw.Pool,
+		Position:       raw.Position,
+		TickLower:      raw.TickLower.int(),
+		TickUpper:      raw.TickUpper.int(),
+		Liquidity:      values["liquidity"],
+		AfterLiquidity: values["after_liquidity"],
+		AmountA:        values["amount_a"],
+		AmountB:        values["amount_b"],
+	}
+	return nil
+}
+
+func (e *RemoveLiquidityEvent) UnmarshalJSON(data []byte) error {
+	var raw liquidityEvent
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	values, err := raw.values()
+	if err != nil {
+		return err
+	}
+	*e = RemoveLiquidityEvent{
+		Pool:           raw.Pool,
+		Position:       raw.Position,
+		TickLower:      raw.TickLower.int(),
+		TickUpper:      raw.TickUpper.int(),
+		Liquidity:      values["liquidity"],
+		AfterLiquidity: values["after_liquidity"],
+		AmountA:        values["amount_a"],
+		AmountB:        values["amount_b"],
+	}
+	return nil
+}
+
+func parseUints(fields map[string]jsonUint) (map[string]*big.Int, error) {
+	values := make(map[string]*big.Int, len(fields))
+	for field, v := range fields {
+		n, err := v.big(field)
+		if err != nil {
+			return nil, err
+		}
+		values[field] = n
+	}
+	return values, nil
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(`{
+		"type": "0x1eabed72c53feb3805120a081dc15963c204dc8d091542592abaf7a35689b2fb::pool::SwapEvent",
+		"parsedJson": {
+			"after_sqrt_price": "18446744073709551516",
+			"amount_in": "100",
+			"amount_out": "98",
+			"atob": true,
+			"before_sqrt_price": "18446744073709551616",
+			"fee_amount": "1",
+			"partner": "0x0",
+			"pool": "0xpool",
+			"ref_amount": "0",
+			"steps": "2",
+			"vault_a_amount": "1100",
+			"vault_b_amount": "902"
+		}
+	}`))
+	if assert.NoError(t, err) && assert.IsType(t, &SwapEvent{}, event) {
+		swap := event.(*SwapEvent)
+		assert.Equal(t, "0xpool", swap.PoolID())
+		assert.True(t, swap.AToB)
+		assert.Equal(t, big.NewInt(100), swap.AmountIn)
+		assert.Equal(t, big.NewInt(98), swap.AmountOut)
+		assert.Equal(t, big.NewInt(1), swap.FeeAmount)
+		assert.Equal(t, big.NewInt(1100), swap.VaultAAmount)
+		assert.Equal(t, "18446744073709551516", swap.AfterSqrtPrice.String())
+		assert.Equal(t, uint64(2), swap.Steps)
+	}
+
+	event, err = ParseEvent([]byte(`{
+		"type": "0x1eab::pool::AddLiquidityEvent",
+		"parsedJson": {
+			"after_liquidity": "2000",
+			"amount_a": "10",
+			"amount_b": "20",
+			"liquidity": "1000",
+			"pool": "0xpool",
+			"position": "0xposition",
+			"tick_lower": {"bits": 4294967196},
+			"tick_upper": {"bits": 100}
+		}
+	}`))
+	if assert.NoError(t, err) && assert.IsType(t, &AddLiquidityEvent{}, event) {
+		add := event.(*AddLiquidityEvent)
+		assert.Equal(t, -100, add.TickLower, "ticks are decoded from their two's complement bits")
+		assert.Equal(t, 100, add.TickUpper)
+		assert.Equal(t, big.NewInt(1000), add.Liquidity)
+		assert.Equal(t, big.NewInt(2000), add.AfterLiquidity)
+		assert.Equal(t, "0xposition", add.Position)
+	}
+
+	event, err = DecodeEvent("0x1eab::pool::RemoveLiquidityEvent", []byte(`{
+		"after_liquidity": "0", "amount_a": "10", "amount_b": "20", "liquidity": "1000",
+		"pool": "0xpool", "position": "0xposition", "tick_lower": {"bits": 4294967196}, "tick_upper": {"bits": 100}
+	}`))
+	if assert.NoError(t, err) && assert.IsType(t, &RemoveLiquidityEvent{}, event) {
+		assert.Equal(t, big.NewInt(1000), event.(*RemoveLiquidityEvent).Liquidity)
+	}
+
+	_, err = DecodeEvent("0x1eab::pool::CollectFeeEvent", []byte(`{}`))
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+
+	_, err = DecodeEvent("0x1eab::pool::RemoveLiquidityEvent", []byte(`{"liquidity": "-1"}`))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
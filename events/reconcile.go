@@ -0,0 +1,238 @@
+package events
+
+import (
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+// The swap modes a swap event can be replayed with, the event itself does not record it
+const (
+	ModeExactInput  = "exact_input"
+	ModeExactOutput = "exact_output"
+	ModePriceLimit  = "price_limit" // exact input stopped by a sqrt price limit at the reported price
+)
+
+// Divergence is a value the replayed swap computes differently from what the swap event reports
+type Divergence struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"` // reported by the event
+	Actual   string `json:"actual"`   // computed by the SDK
+	Delta    string `json:"delta"`    // actual - expected
+}
+
+// Reconciliation is the replay of a swap event against a snapshot of the pool taken before the swap
+type Reconciliation struct {
+	Pool        string       `json:"pool"`
+	Mode        string       `json:"mode"` // the replay the divergences refer to, the one closest to the event
+	Divergences []Divergence `json:"divergences"`
+	Vector      SwapVector   `json:"vector"`
+
+	after *entities.Pool // the pool after the replayed swap
+}
+
+// SwapVector is a self-contained regression vector: a pool state, a swap and the outcome reported on-chain
+type SwapVector struct {
+	Pool     VectorPool    `json:"pool"`
+	AToB     bool          `json:"atob"`
+	Mode     string        `json:"mode"`
+	Amount   string        `json:"amount"`   // the output amount for exact output, the input amount otherwise
+	Expected VectorOutcome `json:"expected"` // for price_limit, the expected sqrt price is also the limit
+}
+
+type VectorPool struct {
+	Fee          uint64       `json:"fee"`
+	TickSpacing  int          `json:"tick_spacing"`
+	SqrtPriceX64 string       `json:"sqrt_price_x64"`
+	Liquidity    string       `json:"liquidity"`
+	TickCurrent  int          `json:"tick_current"`
+	Ticks        []VectorTick `json:"ticks,omitempty"` // only for pools with an entities.TickLister
+}
+
+type VectorTick struct {
+	Index          int    `json:"index"`
+	LiquidityNet   string `json:"liquidity_net"`
+	LiquidityGross string `json:"liquidity_gross"`
+}
+
+type VectorOutcome struct {
+	AmountIn     string `json:"amount_in"`
+	AmountOut    string `json:"amount_out"`
+	FeeAmount    string `json:"fee_amount"`
+	SqrtPriceX64 string `json:"sqrt_price_x64"`
+	Tick         int    `json:"tick"`
+}
+
+// Matches returns whether the replay reproduced the event exactly
+func (r *Reconciliation) Matches() bool {
+	return len(r.Divergences) == 0
+}
+
+/**
+ * Replays a swap event against a snapshot of the pool taken before the swap and reports every value that diverges.
+ * The event does not record whether the swap was exact input, exact output or stopped by a price limit, so each is
+ * replayed in that order until one matches, otherwise the closest one is reported.
+ * @param pool the pool before the swap
+ * @param event the swap event
+ */
+func Reconcile(pool *entities.Pool, event *SwapEvent) (*Reconciliation, error) {
+	expectedTick, crossedTick, err := tickAfterSwap(event)
+	if err != nil {
+		return nil, err
+	}
+	expected := outcome{
+		amountIn:     event.AmountIn,
+		amountOut:    event.AmountOut,
+		feeAmount:    event.FeeAmount,
+		sqrtPriceX64: event.AfterSqrtPrice,
+		tick:         expectedTick,
+	}
+
+	var best *Reconciliation
+	for _, mode := range []string{ModeExactInput, ModeExactOutput, ModePriceLimit} {
+		if mode == ModePriceLimit && event.AfterSqrtPrice.Cmp(pool.SqrtRatioX64) == 0 {
+			// the price did not move, so no price limit stopped the swap
+			continue
+		}
+		actual, err := replay(pool, event, mode)
+		if err != nil {
+			if mode == ModeExactInput {
+				return nil, err
+			}
+			// the event is inconsistent with this mode, e.g. the reported price is on the wrong side
+			continue
+		}
+		r := &Reconciliation{
+			Pool:        event.Pool,
+			Mode:        mode,
+			Divergences: diverge(pool, event, expected, actual, crossedTick),
+			Vector:      newSwapVector(pool, event, mode, expected),
+			after:       actual.pool,
+		}
+		if best == nil || len(r.Divergences) < len(best.Divergences) {
+			best = r
+		}
+		if best.Matches() {
+			break
+		}
+	}
+	return best, nil
+}
+
+type outcome struct {
+	amountIn, amountOut, feeAmount, sqrtPriceX64 *big.Int
+	tick                                         int
+	pool                                         *entities.Pool
+}
+
+func replay(pool *entities.Pool, event *SwapEvent, mode string) (*outcome, error) {
+	inputToken, outputToken := pool.Token1, pool.Token0
+	if event.AToB {
+		inputToken, outputToken = pool.Token0, pool.Token1
+	}
+	var result *entities.SwapResult
+	var err error
+	switch mode {
+	case ModeExactInput:
+		result, err = pool.GetOutputAmount(entities.FromRawAmount(inputToken, event.AmountIn), nil)
+	case ModeExactOutput:
+		result, err = pool.GetInputAmount(entities.FromRawAmount(outputToken, event.AmountOut), nil)
+	case ModePriceLimit:
+		result, err = pool.GetOutputAmount(entities.FromRawAmount(inputToken, event.AmountIn), event.AfterSqrtPrice)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &outcome{
+		amountIn:     result.AmountIn.Quotient(),
+		amountOut:    result.AmountOut.Quotient(),
+		feeAmount:    result.FeeAmount.Quotient(),
+		sqrtPriceX64: result.SqrtRatioX64,
+		tick:         result.TickCurrent,
+		pool:         result.Pool,
+	}, nil
+}
+
+func diverge(pool *entities.Pool, event *SwapEvent, expected outcome, actual *outcome, crossedTick bool) []Divergence {
+	if crossedTick && actual.tick == expected.tick-1 {
+		expected.tick = actual.tick
+	}
+	fields := []struct {
+		field            string
+		expected, actual *big.Int
+	}{
+		{"before_sqrt_price", event.BeforeSqrtPrice, pool.SqrtRatioX64},
+		{"amount_in", expected.amountIn, actual.amountIn},
+		{"amount_out", expected.amountOut, actual.amountOut},
+		{"fee_amount", expected.feeAmount, actual.feeAmount},
+		{"after_sqrt_price", expected.sqrtPriceX64, actual.sqrtPriceX64},
+		{"tick", big.NewInt(int64(expected.tick)), big.NewInt(int64(actual.tick))},
+	}
+	var divergences []Divergence
+	for _, f := range fields {
+		if f.expected.Cmp(f.actual) != 0 {
+			divergences = append(divergences, Divergence{
+				Field:    f.field,
+				Expected: f.expected.String(),
+				Actual:   f.actual.String(),
+				Delta:    new(big.Int).Sub(f.actual, f.expected).String(),
+			})
+		}
+	}
+	return divergences
+}
+
+// tickAfterSwap derives the pool tick from the sqrt price reported after the swap. The event does not report the tick
+// itself: a swap to the left that ends exactly on a tick is at that tick when it stopped at a price limit, but one
+// tick below when it crossed the tick, which is reported as crossedTick.
+func tickAfterSwap(event *SwapEvent) (tick int, crossedTick bool, err error) {
+	tick, err = utils.GetTickAtSqrtRatio(event.AfterSqrtPrice)
+	if err != nil {
+		return 0, false, err
+	}
+	if event.AToB && event.AfterSqrtPrice.Cmp(event.BeforeSqrtPrice) != 0 {
+		sqrtRatioX64, err := utils.GetSqrtRatioAtTick(tick)
+		if err != nil {
+			return 0, false, err
+		}
+		crossedTick = sqrtRatioX64.Cmp(event.AfterSqrtPrice) == 0
+	}
+	return tick, crossedTick, nil
+}
+
+func newSwapVector(pool *entities.Pool, event *SwapEvent, mode string, expected outcome) SwapVector {
+	amount := event.AmountIn
+	if mode == ModeExactOutput {
+		amount = event.AmountOut
+	}
+	v := SwapVector{
+		Pool: VectorPool{
+			Fee:          pool.Fee,
+			TickSpacing:  pool.TickSpacing,
+			SqrtPriceX64: pool.SqrtRatioX64.String(),
+			Liquidity:    pool.Liquidity.String(),
+			TickCurrent:  pool.TickCurrent,
+		},
+		AToB:   event.AToB,
+		Mode:   mode,
+		Amount: amount.String(),
+		Expected: VectorOutcome{
+			AmountIn:     expected.amountIn.String(),
+			AmountOut:    expected.amountOut.String(),
+			FeeAmount:    expected.feeAmount.String(),
+			SqrtPriceX64: expected.sqrtPriceX64.String(),
+			Tick:         expected.tick,
+		},
+	}
+	if lister, ok := pool.TickDataProvider.(entities.TickLister); ok {
+		for _, tick := range lister.Ticks() {
+			v.Pool.Ticks = append(v.Pool.Ticks, VectorTick{
+				Index:          tick.Index,
+				LiquidityNet:   tick.LiquidityNet.String(),
+				LiquidityGross: tick.LiquidityGross.String(),
+			})
+		}
+	}
+	return v
+}
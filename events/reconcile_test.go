@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	pool := newTestPool()
+
+	event := swapEvent(t, pool, true, big.NewInt(1e12))
+	r, err := Reconcile(pool, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, r.Matches())
+	assert.Equal(t, ModeExactInput, r.Mode)
+
+	// exact output swaps round the input up, which an exact input replay does not always reproduce
+	result, err := pool.GetInputAmount(entities.FromRawAmount(USDC, big.NewInt(123456789)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event = &SwapEvent{
+		Pool:            "0xpool",
+		AToB:            true,
+		AmountIn:        result.AmountIn.Quotient(),
+		AmountOut:       result.AmountOut.Quotient(),
+		FeeAmount:       result.FeeAmount.Quotient(),
+		BeforeSqrtPrice: pool.SqrtRatioX64,
+		AfterSqrtPrice:  result.SqrtRatioX64,
+	}
+	r, err = Reconcile(pool, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, r.Matches())
+
+	// swaps ending exactly on a tick to the left are either at the tick or, when they crossed it, one tick below
+	target, _ := utils.GetSqrtRatioAtTick(-60)
+	tick, crossedTick, err := tickAfterSwap(&SwapEvent{AToB: true, BeforeSqrtPrice: pool.SqrtRatioX64, AfterSqrtPrice: target})
+	assert.NoError(t, err)
+	assert.Equal(t, -60, tick)
+	assert.True(t, crossedTick)
+	toTick, err := pool.GetInputAmountToSqrtPrice(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = Reconcile(pool, &SwapEvent{
+		Pool:            "0xpool",
+		AToB:            true,
+		AmountIn:        toTick.AmountIn.Quotient(),
+		AmountOut:       toTick.AmountOut.Quotient(),
+		FeeAmount:       toTick.FeeAmount.Quotient(),
+		BeforeSqrtPrice: pool.SqrtRatioX64,
+		AfterSqrtPrice:  toTick.SqrtRatioX64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, r.Divergences)
+	assert.Equal(t, ModePriceLimit, r.Mode, "a swap stopped by a price limit cannot be replayed without it")
+
+	// divergences are machine readable
+	event = swapEvent(t, pool, false, big.NewInt(1e12))
+	event.FeeAmount = new(big.Int).Sub(event.FeeAmount, big.NewInt(2))
+	r, err = Reconcile(pool, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, r.Matches())
+	assert.Equal(t, ModeExactInput, r.Mode)
+	assert.Equal(t, []Divergence{{
+		Field:    "fee_amount",
+		Expected: event.FeeAmount.String(),
+		Actual:   new(big.Int).Add(event.FeeAmount, big.NewInt(2)).String(),
+		Delta:    "2",
+	}}, r.Divergences)
+
+	data, err := json.Marshal(r)
+	assert.NoError(t, err)
+	var decoded Reconciliation
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, r.Divergences, decoded.Divergences)
+	assert.Equal(t, event.AmountIn.String(), decoded.Vector.Amount)
+	assert.Equal(t, pool.SqrtRatioX64.String(), decoded.Vector.Pool.SqrtPriceX64)
+	assert.Len(t, decoded.Vector.Pool.Ticks, 2)
+	assert.Equal(t, event.FeeAmount.String(), decoded.Vector.Expected.FeeAmount)
+}
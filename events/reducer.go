@@ -0,0 +1,105 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+)
+
+var (
+	ErrWrongPool = errors.New("event belongs to a different pool")
+	ErrMismatch  = errors.New("event does not match the simulated swap")
+)
+
+// MismatchError is returned when the replay of a swap event does not reproduce it. It matches ErrMismatch with
+// errors.Is, the reconciliation holds every divergence.
+type MismatchError struct {
+	Event          *SwapEvent
+	Reconciliation *Reconciliation
+}
+
+func (e *MismatchError) Error() string {
+	d := e.Reconciliation.Divergences[0]
+	msg := fmt.Sprintf("%s: %s is %s, simulated %s", ErrMismatch, d.Field, d.Expected, d.Actual)
+	if n := len(e.Reconciliation.Divergences); n > 1 {
+		msg += fmt.Sprintf(" (and %d more)", n-1)
+	}
+	return msg
+}
+
+func (e *MismatchError) Is(target error) bool {
+	return target == ErrMismatch
+}
+
+// Reducer keeps a local mirror of a pool up to date by applying the pool's events in order. The pool's tick data
+// provider must be an entities.TickLister, so liquidity events can update the ticks.
+type Reducer struct {
+	PoolID string
+	Pool   *entities.Pool
+}
+
+/**
+ * Construct a reducer for a pool
+ * @param poolID the object ID of the pool
+ * @param pool the state of the pool before the first event to apply
+ */
+func NewReducer(poolID string, pool *entities.Pool) (*Reducer, error) {
+	if _, ok := pool.TickDataProvider.(entities.TickLister); !ok {
+		return nil, entities.ErrTicksNotListable
+	}
+	return &Reducer{PoolID: poolID, Pool: pool}, nil
+}
+
+/**
+ * Applies a stream of events in order, stopping at the first event that fails to apply
+ * @param events the events to apply
+ */
+func (r *Reducer) ApplyAll(events []Event) error {
+	for i, event := range events {
+		if err := r.Apply(event); err != nil {
+			return fmt.Errorf("event %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+/**
+ * Applies a single event. Swaps are replayed with Reconcile and must reproduce the reported sqrt prices, amounts and
+ * tick, otherwise a *MismatchError is returned. The pool is left unchanged when an event fails to apply.
+ * @param event the event to apply
+ */
+func (r *Reducer) Apply(event Event) error {
+	if event.PoolID() != r.PoolID {
+		return ErrWrongPool
+	}
+
+	var pool *entities.Pool
+	var err error
+	switch e := event.(type) {
+	case *SwapEvent:
+		pool, err = r.applySwap(e)
+	case *AddLiquidityEvent:
+		pool, err = r.Pool.ApplyMint(e.TickLower, e.TickUpper, e.Liquidity)
+	case *RemoveLiquidityEvent:
+		pool, err = r.Pool.ApplyBurn(e.TickLower, e.TickUpper, e.Liquidity)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnknownEvent, event)
+	}
+	if err != nil {
+		return err
+	}
+	r.Pool = pool
+	return nil
+}
+
+func (r *Reducer) applySwap(e *SwapEvent) (*entities.Pool, error) {
+	reconciliation, err := Reconcile(r.Pool, e)
+	if err != nil {
+		return nil, err
+	}
+	if !reconciliation.Matches() {
+		return nil, &MismatchError{Event: e, Reconciliation: reconciliation}
+	}
+	return reconciliation.after, nil
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/internal/testpools"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	SUI      = testpools.SUI
+	USDC     = testpools.USDC
+	OneEther = big.NewInt(1e18)
+)
+
+func newTestPool() *entities.Pool {
+	return testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)
+}
+
+// swapEvent returns the event the pool would emit for an exact input swap
+func swapEvent(t *testing.T, pool *entities.Pool, aToB bool, amountIn *big.Int) *SwapEvent {
+	token := pool.Token1
+	if aToB {
+		token = pool.Token0
+	}
+	result, err := pool.GetOutputAmount(entities.FromRawAmount(token, amountIn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SwapEvent{
+		Pool:            "0xpool",
+		AToB:            aToB,
+		AmountIn:        result.AmountIn.Quotient(),
+		AmountOut:       result.AmountOut.Quotient(),
+		FeeAmount:       result.FeeAmount.Quotient(),
+		BeforeSqrtPrice: pool.SqrtRatioX64,
+		AfterSqrtPrice:  result.SqrtRatioX64,
+	}
+}
+
+func TestReducer(t *testing.T) {
+	pool := newTestPool()
+	reducer, err := NewReducer("0xpool", pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	liquidity := big.NewInt(1e15)
+	err = reducer.ApplyAll([]Event{
+		swapEvent(t, pool, true, big.NewInt(1e12)),
+		&AddLiquidityEvent{Pool: "0xpool", TickLower: -600, TickUpper: 600, Liquidity: liquidity},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, new(big.Int).Add(OneEther, liquidity), reducer.Pool.Liquidity)
+	tick, err := reducer.Pool.TickDataProvider.GetTick(-600)
+	assert.NoError(t, err)
+	assert.Equal(t, liquidity, tick.LiquidityGross)
+
+	// swaps are verified against the mirrored state, including the liquidity added before
+	event := swapEvent(t, reducer.Pool, false, big.NewInt(5e13))
+	assert.NoError(t, reducer.Apply(event))
+	assert.Equal(t, event.AfterSqrtPrice, reducer.Pool.SqrtRatioX64)
+
+	assert.NoError(t, reducer.Apply(&RemoveLiquidityEvent{Pool: "0xpool", TickLower: -600, TickUpper: 600, Liquidity: liquidity}))
+	assert.Equal(t, OneEther, reducer.Pool.Liquidity)
+
+	// mismatches leave the state unchanged
+	before := reducer.Pool
+	event = swapEvent(t, reducer.Pool, true, big.NewInt(1e12))
+	event.AmountOut = new(big.Int).Add(event.AmountOut, constants.One)
+	err = reducer.Apply(event)
+	assert.ErrorIs(t, err, ErrMismatch)
+	var mismatch *MismatchError
+	if assert.ErrorAs(t, err, &mismatch) {
+		assert.Equal(t, "amount_out", mismatch.Reconciliation.Divergences[0].Field)
+		assert.Equal(t, event.AmountOut.String(), mismatch.Reconciliation.Divergences[0].Expected)
+	}
+	assert.Same(t, before, reducer.Pool)
+
+	event = swapEvent(t, reducer.Pool, true, big.NewInt(1e12))
+	event.BeforeSqrtPrice = constants.Q64
+	assert.ErrorIs(t, reducer.Apply(event), ErrMismatch, "events out of order are detected")
+
+	assert.ErrorIs(t, reducer.Apply(&AddLiquidityEvent{Pool: "0xother"}), ErrWrongPool)
+}
@@ -2,10 +2,7 @@ module github.com/mythril-labs/clmm-sui-sdk
 
 go 1.18
 
-require (
-	github.com/shopspring/decimal v1.3.1
-	github.com/stretchr/testify v1.8.0
-)
+require github.com/stretchr/testify v1.8.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,70 @@
+// Package testpools provides the tokens and pools shared by the tests of the SDK's packages.
+package testpools
+
+import (
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+)
+
+var (
+	SUI  = entities.NewToken(1, "0x2::sui::SUI", 9, "SUI", "Sui")
+	USDC = entities.NewToken(1, "0x5d4b302506645c37ff133b98c4b50a5ae14841659738d6d733d59d0d217a93bf::coin::COIN", 6, "USDC", "USD Coin")
+	USDT = entities.NewToken(1, "0xc060006111016b8a020ad5b33834984a437aaa7d3c74c18e09a95d48aceab08c::coin::COIN", 6, "USDT", "Tether")
+	WETH = entities.NewToken(1, "0xaf8cd5edc19c4512f4259f0bee101a40d41ebed738ade5874359610ef8eeced5::coin::COIN", 8, "WETH", "Wrapped Ether")
+)
+
+/**
+ * Returns a pool at the given tick, backed by a TickListDataProvider with the given ticks. It panics on invalid
+ * arguments.
+ * @param token0 the first token of the pool
+ * @param token1 the second token of the pool
+ * @param fee the fee of the pool, which determines its tick spacing
+ * @param tick the current tick of the pool
+ * @param liquidity the liquidity in range at the current tick
+ * @param ticks the initialized ticks
+ */
+func New(token0, token1 *entities.Token, fee uint64, tick int, liquidity *big.Int, ticks []entities.Tick) *entities.Pool {
+	spacing := constants.TickSpacings[fee]
+	p, err := entities.NewTickListDataProvider(ticks, spacing)
+	if err != nil {
+		panic(err)
+	}
+	sqrtRatioX64, err := utils.GetSqrtRatioAtTick(tick)
+	if err != nil {
+		panic(err)
+	}
+	pool, err := entities.NewPool(token0, token1, fee, spacing, sqrtRatioX64, liquidity, tick, p)
+	if err != nil {
+		panic(err)
+	}
+	return pool
+}
+
+/**
+ * Returns a pool at the given tick with the liquidity over the full range of usable ticks, and no initialized ticks
+ * without liquidity
+ * @param token0 the first token of the pool
+ * @param token1 the second token of the pool
+ * @param fee the fee of the pool, which determines its tick spacing
+ * @param tick the current tick of the pool
+ * @param liquidity the liquidity of the pool
+ */
+func FullRange(token0, token1 *entities.Token, fee uint64, tick int, liquidity int64) *entities.Pool {
+	var ticks []entities.Tick
+	if liquidity != 0 {
+		ticks = FullRangeTicks(fee, big.NewInt(liquidity))
+	}
+	return New(token0, token1, fee, tick, big.NewInt(liquidity), ticks)
+}
+
+// FullRangeTicks returns the ticks that bound liquidity to the full range of usable ticks of the fee's tick spacing
+func FullRangeTicks(fee uint64, liquidity *big.Int) []entities.Tick {
+	spacing := constants.TickSpacings[fee]
+	return []entities.Tick{
+		{Index: entities.NearestUsableTick(utils.MinTick, spacing), LiquidityNet: liquidity, LiquidityGross: liquidity},
+		{Index: entities.NearestUsableTick(utils.MaxTick, spacing), LiquidityNet: new(big.Int).Neg(liquidity), LiquidityGross: liquidity},
+	}
+}
@@ -0,0 +1,224 @@
+package registry
+
+import "math/bits"
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamt is a persistent hash array mapped trie. Updates return a new trie that shares everything but the nodes on the
+// path to the changed entry, about log32(n) of them, with the trie they were derived from, which stays unchanged.
+type hamt[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	size int
+	hash func(K) uint64
+}
+
+// hamtNode is either a branch with a child per set bit of its bitmap, or a leaf with entries sharing one hash, of which
+// there is more than one only on full hash collisions
+type hamtNode[K comparable, V any] struct {
+	bitmap   uint32
+	children []*hamtNode[K, V]
+	entries  []hamtEntry[K, V]
+}
+
+type hamtEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+}
+
+func newHamt[K comparable, V any](hash func(K) uint64) *hamt[K, V] {
+	return &hamt[K, V]{hash: hash}
+}
+
+func (m *hamt[K, V]) len() int {
+	return m.size
+}
+
+func (m *hamt[K, V]) get(key K) (V, bool) {
+	h := m.hash(key)
+	n := m.root
+	for shift := 0; n != nil; shift += hamtBits {
+		if n.entries != nil {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+		bit := uint32(1) << ((h >> shift) & hamtMask)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		n = n.children[bits.OnesCount32(n.bitmap&(bit-1))]
+	}
+	var zero V
+	return zero, false
+}
+
+// set returns a trie with the key set to the value
+func (m *hamt[K, V]) set(key K, value V) *hamt[K, V] {
+	root, added := m.root.set(0, hamtEntry[K, V]{hash: m.hash(key), key: key, value: value})
+	next := &hamt[K, V]{root: root, size: m.size, hash: m.hash}
+	if added {
+		next.size++
+	}
+	return next
+}
+
+// delete returns a trie without the key, or the trie itself if it does not hold the key
+func (m *hamt[K, V]) delete(key K) *hamt[K, V] {
+	root, removed := m.root.delete(0, m.hash(key), key)
+	if !removed {
+		return m
+	}
+	return &hamt[K, V]{root: root, size: m.size - 1, hash: m.hash}
+}
+
+// each calls fn for every entry, in no particular order
+func (m *hamt[K, V]) each(fn func(K, V)) {
+	m.root.each(fn)
+}
+
+func (n *hamtNode[K, V]) set(shift int, e hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if n == nil {
+		return &hamtNode[K, V]{entries: []hamtEntry[K, V]{e}}, true
+	}
+	if n.entries != nil {
+		for i, old := range n.entries {
+			if old.key == e.key {
+				entries := append([]hamtEntry[K, V](nil), n.entries...)
+				entries[i] = e
+				return &hamtNode[K, V]{entries: entries}, false
+			}
+		}
+		if n.entries[0].hash == e.hash {
+			entries := append(append(make([]hamtEntry[K, V], 0, len(n.entries)+1), n.entries...), e)
+			return &hamtNode[K, V]{entries: entries}, true
+		}
+		// the hashes differ from here on, push the leaf down a level and insert next to it
+		bit := uint32(1) << ((n.entries[0].hash >> shift) & hamtMask)
+		branch := &hamtNode[K, V]{bitmap: bit, children: []*hamtNode[K, V]{n}}
+		return branch.set(shift, e)
+	}
+
+	bit := uint32(1) << ((e.hash >> shift) & hamtMask)
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		children := make([]*hamtNode[K, V], 0, len(n.children)+1)
+		children = append(children, n.children[:i]...)
+		children = append(children, &hamtNode[K, V]{entries: []hamtEntry[K, V]{e}})
+		children = append(children, n.children[i:]...)
+		return &hamtNode[K, V]{bitmap: n.bitmap | bit, children: children}, true
+	}
+	child, added := n.children[i].set(shift+hamtBits, e)
+	children := append([]*hamtNode[K, V](nil), n.children...)
+	children[i] = child
+	return &hamtNode[K, V]{bitmap: n.bitmap, children: children}, added
+}
+
+func (n *hamtNode[K, V]) delete(shift int, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if n.entries != nil {
+		for i, e := range n.entries {
+			if e.key == key {
+				if len(n.entries) == 1 {
+					return nil, true
+				}
+				entries := make([]hamtEntry[K, V], 0, len(n.entries)-1)
+				entries = append(entries, n.entries[:i]...)
+				return &hamtNode[K, V]{entries: append(entries, n.entries[i+1:]...)}, true
+			}
+		}
+		return n, false
+	}
+
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	child, removed := n.children[i].delete(shift+hamtBits, hash, key)
+	if !removed {
+		return n, false
+	}
+	if child != nil {
+		children := append([]*hamtNode[K, V](nil), n.children...)
+		children[i] = child
+		return &hamtNode[K, V]{bitmap: n.bitmap, children: children}, true
+	}
+	if len(n.children) == 1 {
+		return nil, true
+	}
+	children := make([]*hamtNode[K, V], 0, len(n.children)-1)
+	children = append(children, n.children[:i]...)
+	children = append(children, n.children[i+1:]...)
+	if len(children) == 1 && children[0].entries != nil {
+		// a lone leaf moves up, leaves are found by scanning their entries at any depth
+		return children[0], true
+	}
+	return &hamtNode[K, V]{bitmap: n.bitmap &^ bit, children: children}, true
+}
+
+func (n *hamtNode[K, V]) each(fn func(K, V)) {
+	if n == nil {
+		return
+	}
+	for _, e := range n.entries {
+		fn(e.key, e.value)
+	}
+	for _, child := range n.children {
+		child.each(fn)
+	}
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// hashString and hashUint feed FNV-1a, the hashes are mixed at the end so that every group of bits the trie consumes
+// depends on all of the input
+func hashString(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+func hashUint(h, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= v & 0xff
+		h *= fnvPrime
+		v >>= 8
+	}
+	return h
+}
+
+// mix is the splitmix64 finalizer
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+func hashID(id string) uint64 {
+	return mix(hashString(fnvOffset, id))
+}
+
+func hashIndexKey(key indexKey) uint64 {
+	h := hashUint(fnvOffset, uint64(key.kind))
+	h = hashUint(h, uint64(key.token0.chainID))
+	h = hashString(h, key.token0.address)
+	h = hashUint(h, uint64(key.token1.chainID))
+	h = hashString(h, key.token1.address)
+	return mix(hashUint(h, key.fee))
+}
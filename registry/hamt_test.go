@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHamt(t *testing.T) {
+	hashes := map[string]func(string) uint64{
+		"id":         hashID,
+		"collisions": func(s string) uint64 { return hashID(s) % 7 }, // full collisions and shared prefixes
+	}
+	for name, hash := range hashes {
+		t.Run(name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			m := newHamt[string, int](hash)
+			want := make(map[string]int)
+			var versions []*hamt[string, int]
+			var wants []map[string]int
+			for i := 0; i < 5000; i++ {
+				key := fmt.Sprintf("0x%x", rnd.Intn(500))
+				if rnd.Intn(3) == 0 {
+					m = m.delete(key)
+					delete(want, key)
+				} else {
+					m = m.set(key, i)
+					want[key] = i
+				}
+				if i%1000 == 0 {
+					copied := make(map[string]int, len(want))
+					for k, v := range want {
+						copied[k] = v
+					}
+					versions, wants = append(versions, m), append(wants, copied)
+				}
+			}
+			versions, wants = append(versions, m), append(wants, want)
+
+			// every version still holds exactly what it held when it was taken
+			for i, version := range versions {
+				assert.Equal(t, len(wants[i]), version.len())
+				got := make(map[string]int)
+				version.each(func(k string, v int) { got[k] = v })
+				assert.Equal(t, wants[i], got)
+				for k, v := range wants[i] {
+					value, ok := version.get(k)
+					assert.True(t, ok)
+					assert.Equal(t, v, value)
+				}
+				_, ok := version.get("0xmissing")
+				assert.False(t, ok)
+			}
+
+			// deleting everything leaves an empty trie
+			keys := make([]string, 0, len(want))
+			for k := range want {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				m = m.delete(k)
+			}
+			assert.Equal(t, 0, m.len())
+			assert.Nil(t, m.root)
+		})
+	}
+}
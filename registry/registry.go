@@ -0,0 +1,382 @@
+// Package registry holds live pools keyed by their object ID.
+//
+// A Registry publishes immutable snapshots: readers take the current Snapshot and quote against it without locking
+// while a writer publishes the next one, so every reader sees a consistent version of all pools. Snapshots are
+// persistent tries, a new version copies only the paths to the pools it changes and shares the rest with the previous
+// one, so updating a pool takes O(log n) regardless of how many pools the registry holds. Adding or removing a pool
+// also copies the index lists of its pair, fee tier and tokens. Pools published to
+// a registry must not be modified afterwards; derive new pools instead, e.g. with Pool.ApplyMint or an
+// events.Reducer, and publish those.
+package registry
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+)
+
+var (
+	ErrEmptyID     = errors.New("pool ID is empty")
+	ErrNilPool     = errors.New("pool is nil")
+	ErrUnknownPool = errors.New("unknown pool")
+)
+
+// Entry is a pool together with its object ID
+type Entry struct {
+	ID   string
+	Pool *entities.Pool
+}
+
+type tokenKey struct {
+	chainID uint
+	address string
+}
+
+// The kinds of index entries
+const (
+	byPairFee = iota // unordered pair and fee
+	byPair           // unordered pair
+	byFee            // fee tier
+	byToken          // either coin type
+)
+
+type indexKey struct {
+	kind           int
+	token0, token1 tokenKey
+	fee            uint64
+}
+
+func keyOf(token *entities.Token) tokenKey {
+	return tokenKey{chainID: token.ChainId(), address: token.Address}
+}
+
+/**
+ * Returns the key of an unordered pair, with the tokens sorted by Token.SortsBefore
+ * @param tokenA one token of the pair
+ * @param tokenB the other token of the pair
+ */
+func pairKey(tokenA, tokenB *entities.Token) (indexKey, error) {
+	before, err := tokenA.SortsBefore(tokenB)
+	if err != nil {
+		return indexKey{}, err
+	}
+	if !before {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	return indexKey{kind: byPair, token0: keyOf(tokenA), token1: keyOf(tokenB)}, nil
+}
+
+// pairFeeKey returns the key of an unordered pair with a fee
+func pairFeeKey(pair indexKey, fee uint64) indexKey {
+	pair.kind = byPairFee
+	pair.fee = fee
+	return pair
+}
+
+// indexKeys returns the keys a pool is indexed under, the pool's tokens must sort
+func indexKeys(pool *entities.Pool) []indexKey {
+	pair, _ := pairKey(pool.Token0, pool.Token1)
+	return []indexKey{
+		pairFeeKey(pair, pool.Fee),
+		pair,
+		{kind: byFee, fee: pool.Fee},
+		{kind: byToken, token0: keyOf(pool.Token0)},
+		{kind: byToken, token0: keyOf(pool.Token1)},
+	}
+}
+
+func validate(e Entry) error {
+	if e.ID == "" {
+		return ErrEmptyID
+	}
+	if e.Pool == nil {
+		return ErrNilPool
+	}
+	_, err := pairKey(e.Pool.Token0, e.Pool.Token1)
+	return err
+}
+
+// Snapshot is an immutable version of the registry
+type Snapshot struct {
+	version uint64
+	pools   *hamt[string, *entities.Pool]
+	index   *hamt[indexKey, []string] // sorted IDs, copied when pools join or leave them
+}
+
+// Version returns the version of the snapshot, which increases with every change published to the registry
+func (s *Snapshot) Version() uint64 {
+	return s.version
+}
+
+// Len returns the number of pools in the snapshot
+func (s *Snapshot) Len() int {
+	return s.pools.len()
+}
+
+/**
+ * Returns the pool with the given object ID
+ * @param id the object ID of the pool
+ */
+func (s *Snapshot) Pool(id string) (*entities.Pool, bool) {
+	return s.pools.get(id)
+}
+
+// IDs returns the object IDs of all pools in ascending order
+func (s *Snapshot) IDs() []string {
+	ids := make([]string, 0, s.pools.len())
+	s.pools.each(func(id string, _ *entities.Pool) {
+		ids = append(ids, id)
+	})
+	sort.Strings(ids)
+	return ids
+}
+
+/**
+ * Returns the pools of a pair in either order with the given fee, ordered by object ID. There can be more than one,
+ * e.g. with the coin types in both orders or with different tick spacings.
+ * @param tokenA one token of the pair
+ * @param tokenB the other token of the pair
+ * @param fee the fee of the pool
+ */
+func (s *Snapshot) Lookup(tokenA, tokenB *entities.Token, fee uint64) ([]Entry, error) {
+	key, err := pairKey(tokenA, tokenB)
+	if err != nil {
+		return nil, err
+	}
+	return s.entries(pairFeeKey(key, fee)), nil
+}
+
+/**
+ * Returns the pools of a pair in either order and with any fee, ordered by object ID
+ * @param tokenA one token of the pair
+ * @param tokenB the other token of the pair
+ */
+func (s *Snapshot) Pools(tokenA, tokenB *entities.Token) ([]Entry, error) {
+	key, err := pairKey(tokenA, tokenB)
+	if err != nil {
+		return nil, err
+	}
+	return s.entries(key), nil
+}
+
+/**
+ * Returns the pools with the given fee, ordered by object ID
+ * @param fee the fee tier
+ */
+func (s *Snapshot) PoolsWithFee(fee uint64) []Entry {
+	return s.entries(indexKey{kind: byFee, fee: fee})
+}
+
+/**
+ * Returns the pools involving the token, ordered by object ID
+ * @param token the token
+ */
+func (s *Snapshot) PoolsWithToken(token *entities.Token) []Entry {
+	return s.entries(indexKey{kind: byToken, token0: keyOf(token)})
+}
+
+/**
+ * Returns the candidate pools for routing a swap between two tokens: the pools between any two of the tokens and the
+ * base tokens used as intermediate hops, ordered by object ID
+ * @param tokenIn the input token
+ * @param tokenOut the output token
+ * @param bases the tokens routes may pass through
+ */
+func (s *Snapshot) Candidates(tokenIn, tokenOut *entities.Token, bases ...*entities.Token) ([]Entry, error) {
+	tokens := append([]*entities.Token{tokenIn, tokenOut}, bases...)
+	seen := make(map[string]bool)
+	var ids []string
+	for i, a := range tokens {
+		for _, b := range tokens[i+1:] {
+			if a.Equal(b) {
+				continue
+			}
+			key, err := pairKey(a, b)
+			if err != nil {
+				return nil, err
+			}
+			pairIDs, _ := s.index.get(key)
+			for _, id := range pairIDs {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	sort.Strings(ids)
+	return s.entriesOf(ids), nil
+}
+
+// entries returns the pools indexed under the key
+func (s *Snapshot) entries(key indexKey) []Entry {
+	ids, _ := s.index.get(key)
+	return s.entriesOf(ids)
+}
+
+func (s *Snapshot) entriesOf(ids []string) []Entry {
+	entries := make([]Entry, len(ids))
+	for i, id := range ids {
+		pool, _ := s.pools.get(id)
+		entries[i] = Entry{ID: id, Pool: pool}
+	}
+	return entries
+}
+
+// Registry publishes snapshots of pools. It is safe for concurrent use; writers are serialized.
+type Registry struct {
+	mu      sync.Mutex   // serializes writers
+	current atomic.Value // *Snapshot
+}
+
+// New returns an empty registry
+func New() *Registry {
+	r := &Registry{}
+	r.current.Store(&Snapshot{pools: newHamt[string, *entities.Pool](hashID), index: newHamt[indexKey, []string](hashIndexKey)})
+	return r
+}
+
+// Snapshot returns the current snapshot, which stays unchanged while later ones are published
+func (r *Registry) Snapshot() *Snapshot {
+	return r.current.Load().(*Snapshot)
+}
+
+/**
+ * Publishes pools, replacing those with the same object IDs, in a single new version
+ * @param entries the pools to publish
+ * @returns the new version
+ */
+func (r *Registry) Publish(entries ...Entry) (uint64, error) {
+	for _, e := range entries {
+		if err := validate(e); err != nil {
+			return 0, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := r.Snapshot().next()
+	for _, e := range entries {
+		next.put(e.ID, e.Pool)
+	}
+	return r.store(next), nil
+}
+
+/**
+ * Removes pools. Unknown IDs are ignored.
+ * @param ids the object IDs of the pools to remove
+ * @returns the new version
+ */
+func (r *Registry) Remove(ids ...string) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := r.Snapshot().next()
+	for _, id := range ids {
+		if old, ok := next.pools.get(id); ok {
+			next.unindex(id, indexKeys(old))
+			next.pools = next.pools.delete(id)
+		}
+	}
+	return r.store(next)
+}
+
+/**
+ * Replaces a pool with the one derived from it by update, without a writer publishing in between
+ * @param id the object ID of the pool
+ * @param update derives the new pool from the current one, it must not modify the current one
+ * @returns the new version
+ */
+func (r *Registry) Update(id string, update func(*entities.Pool) (*entities.Pool, error)) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.Snapshot()
+	old, ok := current.pools.get(id)
+	if !ok {
+		return 0, ErrUnknownPool
+	}
+	pool, err := update(old)
+	if err != nil {
+		return 0, err
+	}
+	if err := validate(Entry{ID: id, Pool: pool}); err != nil {
+		return 0, err
+	}
+	next := current.next()
+	next.put(id, pool)
+	return r.store(next), nil
+}
+
+func (r *Registry) store(next *Snapshot) uint64 {
+	next.version++
+	r.current.Store(next)
+	return next.version
+}
+
+// next returns a copy of the snapshot to derive the next version from, which shares the tries of this one
+func (s *Snapshot) next() *Snapshot {
+	return &Snapshot{version: s.version, pools: s.pools, index: s.index}
+}
+
+// put sets the pool with the given ID, reindexing it only if its index keys change
+func (s *Snapshot) put(id string, pool *entities.Pool) {
+	keys := indexKeys(pool)
+	if old, ok := s.pools.get(id); ok {
+		oldKeys := indexKeys(old)
+		if sameKeys(oldKeys, keys) {
+			s.pools = s.pools.set(id, pool)
+			return
+		}
+		s.unindex(id, oldKeys)
+	}
+	s.pools = s.pools.set(id, pool)
+	for _, key := range keys {
+		ids, _ := s.index.get(key)
+		s.index = s.index.set(key, insertID(ids, id))
+	}
+}
+
+func (s *Snapshot) unindex(id string, keys []indexKey) {
+	for _, key := range keys {
+		ids, _ := s.index.get(key)
+		if ids = removeID(ids, id); len(ids) > 0 {
+			s.index = s.index.set(key, ids)
+		} else {
+			s.index = s.index.delete(key)
+		}
+	}
+}
+
+func sameKeys(a, b []indexKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// insertID returns a copy of the sorted ids with id added
+func insertID(ids []string, id string) []string {
+	i := sort.SearchStrings(ids, id)
+	next := make([]string, 0, len(ids)+1)
+	next = append(next, ids[:i]...)
+	next = append(next, id)
+	return append(next, ids[i:]...)
+}
+
+// removeID returns a copy of the sorted ids without id
+func removeID(ids []string, id string) []string {
+	i := sort.SearchStrings(ids, id)
+	if i == len(ids) || ids[i] != id {
+		return ids
+	}
+	next := make([]string, 0, len(ids)-1)
+	next = append(next, ids[:i]...)
+	return append(next, ids[i+1:]...)
+}
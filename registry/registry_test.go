@@ -0,0 +1,219 @@
+package registry
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/internal/testpools"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	SUI  = testpools.SUI
+	USDC = testpools.USDC
+	USDT = testpools.USDT
+)
+
+func TestRegistry(t *testing.T) {
+	r := New()
+	empty := r.Snapshot()
+	assert.Equal(t, uint64(0), empty.Version())
+	assert.Equal(t, 0, empty.Len())
+
+	medium := testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)
+	low := testpools.FullRange(SUI, USDC, constants.FeeLow, 0, 1e18)
+	version, err := r.Publish(Entry{ID: "0xb", Pool: medium}, Entry{ID: "0xa", Pool: low})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), version)
+
+	s := r.Snapshot()
+	assert.Equal(t, []string{"0xa", "0xb"}, s.IDs())
+	pool, ok := s.Pool("0xb")
+	assert.True(t, ok)
+	assert.Same(t, medium, pool)
+	assert.Equal(t, []Entry{{ID: "0xb", Pool: medium}}, lookup(t, s, SUI, USDC, constants.FeeMedium))
+	assert.Empty(t, lookup(t, s, SUI, USDC, constants.FeeHigh))
+	assert.Equal(t, 0, empty.Len(), "earlier snapshots are unchanged")
+
+	// a second pool with the same pair and fee
+	twin := testpools.FullRange(SUI, USDC, constants.FeeMedium, 10, 1e18)
+	_, err = r.Publish(Entry{ID: "0x0", Pool: twin})
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{{ID: "0x0", Pool: twin}, {ID: "0xb", Pool: medium}}, lookup(t, r.Snapshot(), SUI, USDC, constants.FeeMedium))
+
+	// replacing a pool under another pair reindexes it
+	moved := testpools.FullRange(USDC, USDT, constants.FeeMedium, 0, 1e18)
+	version, err = r.Publish(Entry{ID: "0xb", Pool: moved})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), version)
+	assert.Equal(t, []Entry{{ID: "0x0", Pool: twin}}, lookup(t, r.Snapshot(), SUI, USDC, constants.FeeMedium))
+	assert.Equal(t, []Entry{{ID: "0xb", Pool: moved}}, lookup(t, r.Snapshot(), USDC, USDT, constants.FeeMedium))
+	assert.Equal(t, []Entry{{ID: "0xb", Pool: medium}}, lookup(t, s, SUI, USDC, constants.FeeMedium))
+
+	version, err = r.Update("0xa", func(p *entities.Pool) (*entities.Pool, error) {
+		return p.ApplyMint(-10, 10, big.NewInt(5))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), version)
+	pool, _ = r.Snapshot().Pool("0xa")
+	assert.Equal(t, big.NewInt(1e18+5), pool.Liquidity)
+	assert.Equal(t, big.NewInt(1e18), low.Liquidity)
+	_, err = r.Update("0xc", func(p *entities.Pool) (*entities.Pool, error) { return p, nil })
+	assert.ErrorIs(t, err, ErrUnknownPool)
+
+	assert.Equal(t, uint64(5), r.Remove("0xa", "0xb", "0xc"))
+	assert.Equal(t, []string{"0x0"}, r.Snapshot().IDs())
+	assert.Empty(t, lookup(t, r.Snapshot(), USDC, USDT, constants.FeeMedium))
+
+	_, err = r.Publish(Entry{ID: "", Pool: low})
+	assert.ErrorIs(t, err, ErrEmptyID)
+	_, err = r.Publish(Entry{ID: "0xd"})
+	assert.ErrorIs(t, err, ErrNilPool)
+	assert.Equal(t, uint64(5), r.Snapshot().Version())
+}
+
+func TestConsistentReads(t *testing.T) {
+	r := New()
+	const versions = 200
+	pools := make([]*entities.Pool, versions)
+	for i := range pools {
+		pools[i] = testpools.FullRange(SUI, USDC, constants.FeeMedium, i, 1e18)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last uint64
+			for last < versions {
+				s := r.Snapshot()
+				assert.GreaterOrEqual(t, s.Version(), last)
+				last = s.Version()
+				a, okA := s.Pool("0xa")
+				b, okB := s.Pool("0xb")
+				if assert.Equal(t, okA, okB) && okA {
+					// both pools are published together, so a snapshot never mixes versions
+					assert.Equal(t, a.TickCurrent, b.TickCurrent)
+					assert.Equal(t, int(s.Version())-1, a.TickCurrent)
+				}
+			}
+		}()
+	}
+	for _, pool := range pools {
+		_, err := r.Publish(Entry{ID: "0xa", Pool: pool}, Entry{ID: "0xb", Pool: pool})
+		assert.NoError(t, err)
+	}
+	wg.Wait()
+}
+
+func TestIndexes(t *testing.T) {
+	WETH := testpools.WETH
+	suiUSDC := testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)
+	usdcSUI := testpools.FullRange(USDC, SUI, constants.FeeLow, 0, 1e18)
+	usdcUSDT := testpools.FullRange(USDC, USDT, constants.FeeLowest, 0, 1e18)
+	wethUSDC := testpools.FullRange(WETH, USDC, constants.FeeMedium, 0, 1e18)
+	wethSUI := testpools.FullRange(WETH, SUI, constants.FeeHigh, 0, 1e18)
+
+	r := New()
+	_, err := r.Publish(
+		Entry{ID: "0x1", Pool: suiUSDC},
+		Entry{ID: "0x2", Pool: usdcSUI},
+		Entry{ID: "0x3", Pool: usdcUSDT},
+		Entry{ID: "0x4", Pool: wethUSDC},
+		Entry{ID: "0x5", Pool: wethSUI},
+	)
+	assert.NoError(t, err)
+	s := r.Snapshot()
+
+	pools, err := s.Pools(USDC, SUI)
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{{ID: "0x1", Pool: suiUSDC}, {ID: "0x2", Pool: usdcSUI}}, pools)
+	pools, err = s.Pools(SUI, USDC)
+	assert.NoError(t, err)
+	assert.Len(t, pools, 2, "pairs are unordered")
+	pools, err = s.Pools(SUI, USDT)
+	assert.NoError(t, err)
+	assert.Empty(t, pools)
+	_, err = s.Pools(SUI, SUI)
+	assert.ErrorIs(t, err, entities.ErrSameAddress)
+
+	// pools with the coin types in either order are found by pair and fee
+	assert.Equal(t, []Entry{{ID: "0x2", Pool: usdcSUI}}, lookup(t, s, SUI, USDC, constants.FeeLow))
+	assert.Equal(t, []Entry{{ID: "0x1", Pool: suiUSDC}}, lookup(t, s, USDC, SUI, constants.FeeMedium))
+	_, err = s.Lookup(SUI, SUI, constants.FeeMedium)
+	assert.ErrorIs(t, err, entities.ErrSameAddress)
+
+	assert.Equal(t, []Entry{{ID: "0x1", Pool: suiUSDC}, {ID: "0x4", Pool: wethUSDC}}, s.PoolsWithFee(constants.FeeMedium))
+	assert.Len(t, s.PoolsWithToken(USDC), 4)
+	assert.Len(t, s.PoolsWithToken(WETH), 2)
+
+	// SUI to USDT through USDC or WETH: every pool but the WETH/SUI one when only USDC is a base
+	candidates, err := s.Candidates(SUI, USDT, USDC)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2", "0x3"}, ids(candidates))
+	candidates, err = s.Candidates(SUI, USDT, USDC, WETH)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2", "0x3", "0x4", "0x5"}, ids(candidates))
+	candidates, err = s.Candidates(SUI, USDC, SUI)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x2"}, ids(candidates))
+
+	// removing and replacing pools keeps every index in step
+	r.Remove("0x1")
+	_, err = r.Publish(Entry{ID: "0x4", Pool: testpools.FullRange(WETH, USDT, constants.FeeHigh, 0, 1e18)})
+	assert.NoError(t, err)
+	s = r.Snapshot()
+	pools, _ = s.Pools(SUI, USDC)
+	assert.Equal(t, []string{"0x2"}, ids(pools))
+	assert.Empty(t, s.PoolsWithFee(constants.FeeMedium))
+	assert.Equal(t, []string{"0x2", "0x3"}, ids(s.PoolsWithToken(USDC)))
+	assert.Equal(t, []string{"0x4", "0x5"}, ids(s.PoolsWithFee(constants.FeeHigh)))
+
+	other := entities.NewToken(2, "0x2::sui::SUI", 9, "SUI", "Sui")
+	_, err = r.Publish(Entry{ID: "0x6", Pool: testpools.FullRange(other, USDC, constants.FeeMedium, 0, 1e18)})
+	assert.ErrorIs(t, err, entities.ErrDifferentChain)
+}
+
+// lookup looks up a pair and fee in both orders of the pair, which must agree
+func lookup(t *testing.T, s *Snapshot, tokenA, tokenB *entities.Token, fee uint64) []Entry {
+	entries, err := s.Lookup(tokenA, tokenB, fee)
+	assert.NoError(t, err)
+	reversed, err := s.Lookup(tokenB, tokenA, fee)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, reversed)
+	return entries
+}
+
+func ids(entries []Entry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	for _, n := range []int{100, 10000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			r := New()
+			pool := testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)
+			entries := make([]Entry, n)
+			for i := range entries {
+				entries[i] = Entry{ID: fmt.Sprintf("0x%x", i), Pool: pool}
+			}
+			if _, err := r.Publish(entries...); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = r.Update(entries[i%n].ID, func(p *entities.Pool) (*entities.Pool, error) { return p, nil })
+			}
+		})
+	}
+}
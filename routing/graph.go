@@ -0,0 +1,150 @@
+// Package routing builds a graph of tokens connected by pools and finds candidate paths between tokens for quoting
+// and routing layers.
+package routing
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/registry"
+)
+
+var (
+	ErrSameToken       = errors.New("input and output token are the same")
+	ErrUnknownToken    = errors.New("token is not in the graph")
+	ErrInvalidMaxHops  = errors.New("max hops must be positive")
+	ErrInvalidMaxPaths = errors.New("max paths must be positive")
+)
+
+// Weighting decides the weight of an edge, paths with lower total weight are better candidates
+type Weighting int
+
+const (
+	// WeightLiquidity weighs an edge by the inverse of its pool's liquidity, so paths through deep pools and with
+	// fewer hops come first
+	WeightLiquidity Weighting = iota
+	// WeightMidPrice weighs an edge by the negative log of its mid-price net of the fee, so the total weight of a
+	// path is the negative log of its rate for infinitesimal amounts
+	WeightMidPrice
+)
+
+var ln2 = math.Log(2)
+
+// Edge is a swap direction of a pool
+type Edge struct {
+	ID       string
+	Pool     *entities.Pool
+	TokenIn  *entities.Token
+	TokenOut *entities.Token
+	Weight   float64
+}
+
+// ZeroForOne returns whether the edge swaps token0 for token1
+func (e Edge) ZeroForOne() bool {
+	return e.TokenIn.Equal(e.Pool.Token0)
+}
+
+type tokenKey struct {
+	chainID uint
+	address string
+}
+
+func keyOf(token *entities.Token) tokenKey {
+	return tokenKey{chainID: token.ChainId(), address: token.Address}
+}
+
+// Graph is a directed graph of tokens with an edge for each swap direction of a pool. It is immutable and safe for
+// concurrent use.
+type Graph struct {
+	weighting Weighting
+	tokens    map[tokenKey]*entities.Token
+	edges     map[tokenKey][]Edge // outgoing edges by pool ID
+}
+
+/**
+ * Builds the graph of the given pools, e.g. the candidates of a registry snapshot
+ * @param pools the pools to build the graph of
+ * @param weighting how edges are weighed
+ */
+func NewGraph(pools []registry.Entry, weighting Weighting) *Graph {
+	g := &Graph{
+		weighting: weighting,
+		tokens:    make(map[tokenKey]*entities.Token),
+		edges:     make(map[tokenKey][]Edge),
+	}
+	for _, e := range pools {
+		g.tokens[keyOf(e.Pool.Token0)] = e.Pool.Token0
+		g.tokens[keyOf(e.Pool.Token1)] = e.Pool.Token1
+		for _, zeroForOne := range []bool{true, false} {
+			edge := Edge{ID: e.ID, Pool: e.Pool, TokenIn: e.Pool.Token0, TokenOut: e.Pool.Token1}
+			if !zeroForOne {
+				edge.TokenIn, edge.TokenOut = edge.TokenOut, edge.TokenIn
+			}
+			edge.Weight = weigh(weighting, edge.Pool, zeroForOne)
+			key := keyOf(edge.TokenIn)
+			g.edges[key] = append(g.edges[key], edge)
+		}
+	}
+	for _, edges := range g.edges {
+		sort.SliceStable(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+	}
+	return g
+}
+
+func weigh(weighting Weighting, pool *entities.Pool, zeroForOne bool) float64 {
+	if weighting == WeightMidPrice {
+		rate := LogPrice(pool.SqrtRatioX64)
+		if !zeroForOne {
+			rate = -rate
+		}
+		return -(rate + math.Log1p(-float64(pool.Fee)/float64(constants.FeeMax)))
+	}
+	if pool.Liquidity.Sign() == 0 {
+		return math.Inf(1)
+	}
+	liquidity, _ := new(big.Float).SetInt(pool.Liquidity).Float64()
+	return 1 / liquidity
+}
+
+/**
+ * Returns the natural log of the price of token0 in token1, in raw amounts, at the given sqrt price
+ * @param sqrtRatioX64 the sqrt price as a Q64.64
+ */
+func LogPrice(sqrtRatioX64 *big.Int) float64 {
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(sqrtRatioX64).MantExp(mant)
+	m, _ := mant.Float64()
+	return 2 * (math.Log(m) + float64(exp-64)*ln2)
+}
+
+// Weighting returns how the graph's edges are weighed
+func (g *Graph) Weighting() Weighting {
+	return g.weighting
+}
+
+// Tokens returns the tokens of the graph ordered by address
+func (g *Graph) Tokens() []*entities.Token {
+	tokens := make([]*entities.Token, 0, len(g.tokens))
+	for _, token := range g.tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].ChainId() != tokens[j].ChainId() {
+			return tokens[i].ChainId() < tokens[j].ChainId()
+		}
+		return tokens[i].Address < tokens[j].Address
+	})
+	return tokens
+}
+
+/**
+ * Returns the edges leaving a token, ordered by pool ID
+ * @param token the input token of the edges
+ */
+func (g *Graph) Edges(token *entities.Token) []Edge {
+	return append([]Edge(nil), g.edges[keyOf(token)]...)
+}
@@ -0,0 +1,143 @@
+package routing
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/internal/testpools"
+	"github.com/mythril-labs/clmm-sui-sdk/registry"
+	"github.com/mythril-labs/clmm-sui-sdk/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	SUI  = testpools.SUI
+	USDC = testpools.USDC
+	USDT = testpools.USDT
+	WETH = testpools.WETH
+)
+
+func testPools() []registry.Entry {
+	return []registry.Entry{
+		{ID: "0x1", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)},
+		{ID: "0x2", Pool: testpools.FullRange(USDC, USDT, constants.FeeLowest, 0, 1e15)},
+		{ID: "0x3", Pool: testpools.FullRange(SUI, USDT, constants.FeeMedium, 0, 1e12)},
+		{ID: "0x4", Pool: testpools.FullRange(SUI, WETH, constants.FeeHigh, 0, 1e18)},
+		{ID: "0x5", Pool: testpools.FullRange(WETH, USDT, constants.FeeHigh, 0, 1e18)},
+		{ID: "0x6", Pool: testpools.FullRange(WETH, USDC, constants.FeeMedium, 0, 0)},
+	}
+}
+
+func pathIDs(paths []Path) [][]string {
+	ids := make([][]string, len(paths))
+	for i, p := range paths {
+		for _, e := range p.Edges {
+			ids[i] = append(ids[i], e.ID)
+		}
+	}
+	return ids
+}
+
+func TestPaths(t *testing.T) {
+	g := NewGraph(testPools(), WeightLiquidity)
+	assert.Len(t, g.Tokens(), 4)
+	assert.Len(t, g.Edges(SUI), 3)
+	assert.True(t, g.Edges(SUI)[0].ZeroForOne())
+
+	// the deep two-hop paths beat the shallow direct pool
+	paths, err := g.Paths(SUI, USDT)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x4", "0x5"}, {"0x1", "0x2"}, {"0x3"}}, pathIDs(paths))
+	assert.Equal(t, []*entities.Token{SUI, WETH, USDT}, paths[0].Tokens())
+	assert.InDelta(t, 2e-18, paths[0].Weight, 1e-30)
+
+	paths, err = g.Paths(SUI, USDT, WithMaxHops(1))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x3"}}, pathIDs(paths))
+
+	paths, err = g.Paths(SUI, USDT, WithIntermediates(USDC))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x1", "0x2"}, {"0x3"}}, pathIDs(paths))
+
+	paths, err = g.Paths(SUI, USDT, WithMaxPaths(1))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x4", "0x5"}}, pathIDs(paths))
+
+	// the WETH/USDC pool has no liquidity
+	paths, err = g.Paths(WETH, USDC, WithMaxHops(2))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x4", "0x1"}, {"0x5", "0x2"}}, pathIDs(paths))
+	paths, err = g.Paths(WETH, USDC, WithMaxHops(2), WithZeroLiquidity())
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x4", "0x1"}, {"0x5", "0x2"}, {"0x6"}}, pathIDs(paths))
+	assert.True(t, math.IsInf(paths[2].Weight, 1))
+
+	_, err = g.Paths(SUI, SUI)
+	assert.ErrorIs(t, err, ErrSameToken)
+	_, err = g.Paths(SUI, entities.NewToken(1, "0x9::coin::COIN", 9, "X", "X"))
+	assert.ErrorIs(t, err, ErrUnknownToken)
+	_, err = g.Paths(SUI, USDT, WithMaxHops(0))
+	assert.ErrorIs(t, err, ErrInvalidMaxHops)
+	_, err = g.Paths(SUI, USDT, WithMaxPaths(-1))
+	assert.ErrorIs(t, err, ErrInvalidMaxPaths)
+	_, err = g.Paths(SUI, USDT, WithMaxPaths(0))
+	assert.ErrorIs(t, err, ErrInvalidMaxPaths)
+
+	// equally good paths are ordered by pool IDs
+	twins := NewGraph([]registry.Entry{
+		{ID: "0xb", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)},
+		{ID: "0xa", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)},
+		{ID: "0xc", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 0, 1e18)},
+	}, WeightLiquidity)
+	paths, err = twins.Paths(SUI, USDC)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0xa"}, {"0xb"}, {"0xc"}}, pathIDs(paths))
+}
+
+func TestMidPriceWeighting(t *testing.T) {
+	assert.InDelta(t, 0, LogPrice(constants.Q64), 1e-15)
+	assert.InDelta(t, 1000*math.Log(1.0001), LogPrice(sqrtRatioAt(1000)), 1e-9)
+	assert.InDelta(t, -50000*math.Log(1.0001), LogPrice(sqrtRatioAt(-50000)), 1e-9)
+
+	// the same pair at a better price through the second pool, despite its higher fee
+	pools := []registry.Entry{
+		{ID: "0x1", Pool: testpools.FullRange(SUI, USDC, constants.FeeLowest, 0, 1e18)},
+		{ID: "0x2", Pool: testpools.FullRange(SUI, USDC, constants.FeeMedium, 100, 1e18)},
+	}
+	g := NewGraph(pools, WeightMidPrice)
+	assert.Equal(t, WeightMidPrice, g.Weighting())
+	paths, err := g.Paths(SUI, USDC)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x2"}, {"0x1"}}, pathIDs(paths))
+	assert.InDelta(t, -(100*math.Log(1.0001) + math.Log(1-0.0025)), paths[0].Weight, 1e-9)
+	paths, err = g.Paths(USDC, SUI)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0x1"}, {"0x2"}}, pathIDs(paths))
+}
+
+func TestPathGetOutputAmount(t *testing.T) {
+	g := NewGraph(testPools(), WeightLiquidity)
+	paths, err := g.Paths(SUI, USDT, WithIntermediates(WETH))
+	assert.NoError(t, err)
+
+	input := entities.FromRawAmount(SUI, big.NewInt(1e9))
+	output, results, err := paths[0].GetOutputAmount(input)
+	assert.NoError(t, err)
+	assert.True(t, output.Currency.Equal(USDT))
+	assert.Len(t, results, 2)
+	assert.Equal(t, results[0].AmountOut, results[1].AmountIn)
+	// two 1% fees at price 1, and a little price impact
+	assert.Equal(t, -1, output.Quotient().Cmp(big.NewInt(980100000)))
+	assert.Equal(t, 1, output.Quotient().Cmp(big.NewInt(980000000)))
+}
+
+func sqrtRatioAt(tick int) *big.Int {
+	sqrtRatioX64, err := utils.GetSqrtRatioAtTick(tick)
+	if err != nil {
+		panic(err)
+	}
+	return sqrtRatioX64
+}
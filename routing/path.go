@@ -0,0 +1,173 @@
+package routing
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+)
+
+// Path is a sequence of edges from an input token to an output token
+type Path struct {
+	Edges  []Edge
+	Weight float64 // the sum of the edges' weights
+}
+
+// Hops returns the number of pools the path swaps through
+func (p Path) Hops() int {
+	return len(p.Edges)
+}
+
+// Tokens returns the tokens along the path, from the input to the output token
+func (p Path) Tokens() []*entities.Token {
+	tokens := []*entities.Token{p.Edges[0].TokenIn}
+	for _, e := range p.Edges {
+		tokens = append(tokens, e.TokenOut)
+	}
+	return tokens
+}
+
+/**
+ * Quotes an exact input swap along the path against the pools of the graph
+ * @param inputAmount the amount of the path's input token to swap
+ * @param opts the options of each hop's swap
+ * @returns the amount of the output token and the swap result of each hop
+ */
+func (p Path) GetOutputAmount(inputAmount *entities.CurrencyAmount, opts ...entities.SwapOption) (*entities.CurrencyAmount, []*entities.SwapResult, error) {
+	results := make([]*entities.SwapResult, 0, len(p.Edges))
+	amount := inputAmount
+	for _, e := range p.Edges {
+		result, err := e.Pool.GetOutputAmount(amount, nil, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, result)
+		amount = result.AmountOut
+	}
+	return amount, results, nil
+}
+
+type pathOptions struct {
+	maxHops       int
+	maxPaths      int
+	intermediates map[tokenKey]bool // nil allows any token
+	zeroLiquidity bool
+}
+
+// PathOption configures the paths returned by Graph.Paths
+type PathOption func(*pathOptions)
+
+// WithMaxHops limits paths to the given number of pools, 3 by default
+func WithMaxHops(hops int) PathOption {
+	return func(o *pathOptions) {
+		o.maxHops = hops
+	}
+}
+
+// WithMaxPaths returns only the given number of best paths, all paths by default
+func WithMaxPaths(paths int) PathOption {
+	return func(o *pathOptions) {
+		o.maxPaths = paths
+	}
+}
+
+// WithIntermediates allows only the given tokens between the input and the output token
+func WithIntermediates(tokens ...*entities.Token) PathOption {
+	return func(o *pathOptions) {
+		o.intermediates = make(map[tokenKey]bool, len(tokens))
+		for _, token := range tokens {
+			o.intermediates[keyOf(token)] = true
+		}
+	}
+}
+
+// WithZeroLiquidity includes pools without liquidity in range, which are excluded by default
+func WithZeroLiquidity() PathOption {
+	return func(o *pathOptions) {
+		o.zeroLiquidity = true
+	}
+}
+
+/**
+ * Returns the paths from tokenIn to tokenOut that visit no token and no pool twice, best first: by weight, then by
+ * number of hops, then by pool IDs
+ * @param tokenIn the input token
+ * @param tokenOut the output token
+ * @param opts the options limiting the paths
+ */
+func (g *Graph) Paths(tokenIn, tokenOut *entities.Token, opts ...PathOption) ([]Path, error) {
+	o := &pathOptions{maxHops: 3, maxPaths: math.MaxInt}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.maxHops <= 0 {
+		return nil, ErrInvalidMaxHops
+	}
+	if o.maxPaths <= 0 {
+		return nil, ErrInvalidMaxPaths
+	}
+	if tokenIn.Equal(tokenOut) {
+		return nil, ErrSameToken
+	}
+	if g.tokens[keyOf(tokenIn)] == nil || g.tokens[keyOf(tokenOut)] == nil {
+		return nil, ErrUnknownToken
+	}
+
+	var paths []Path
+	visited := map[tokenKey]bool{keyOf(tokenIn): true}
+	var edges []Edge
+	var walk func(token tokenKey)
+	walk = func(token tokenKey) {
+		for _, e := range g.edges[token] {
+			next := keyOf(e.TokenOut)
+			if visited[next] || (!o.zeroLiquidity && e.Pool.Liquidity.Sign() == 0) {
+				continue
+			}
+			if next == keyOf(tokenOut) {
+				paths = append(paths, newPath(append(edges, e)))
+				continue
+			}
+			if len(edges)+1 == o.maxHops || (o.intermediates != nil && !o.intermediates[next]) {
+				continue
+			}
+			visited[next] = true
+			edges = append(edges, e)
+			walk(next)
+			edges = edges[:len(edges)-1]
+			visited[next] = false
+		}
+	}
+	walk(keyOf(tokenIn))
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		if paths[i].Weight != paths[j].Weight {
+			return paths[i].Weight < paths[j].Weight
+		}
+		if paths[i].Hops() != paths[j].Hops() {
+			return paths[i].Hops() < paths[j].Hops()
+		}
+		return paths[i].lessIDs(paths[j])
+	})
+	if len(paths) > o.maxPaths {
+		paths = paths[:o.maxPaths]
+	}
+	return paths, nil
+}
+
+// lessIDs compares the pool IDs of two paths with the same number of hops, hop by hop
+func (p Path) lessIDs(other Path) bool {
+	for i, e := range p.Edges {
+		if e.ID != other.Edges[i].ID {
+			return e.ID < other.Edges[i].ID
+		}
+	}
+	return false
+}
+
+func newPath(edges []Edge) Path {
+	p := Path{Edges: append([]Edge(nil), edges...)}
+	for _, e := range edges {
+		p.Weight += e.Weight
+	}
+	return p
+}
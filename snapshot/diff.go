@@ -0,0 +1,148 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+)
+
+var ErrDifferentPools = errors.New("pools have different tokens")
+
+// The kinds of tick changes
+const (
+	TickAdded   = "added"
+	TickRemoved = "removed"
+	TickChanged = "changed"
+)
+
+// FieldChange is a pool field whose value differs between two snapshots
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Delta string `json:"delta"` // new - old
+}
+
+// TickChange is an initialized tick that differs between two snapshots. A tick missing from one snapshot has zero
+// liquidity in it.
+type TickChange struct {
+	Index             int    `json:"index"`
+	Kind              string `json:"kind"`
+	OldLiquidityNet   string `json:"old_liquidity_net"`
+	NewLiquidityNet   string `json:"new_liquidity_net"`
+	OldLiquidityGross string `json:"old_liquidity_gross"`
+	NewLiquidityGross string `json:"new_liquidity_gross"`
+}
+
+// PoolDiff lists what changed in a pool between two snapshots, with ticks in ascending order
+type PoolDiff struct {
+	Fields []FieldChange `json:"fields"`
+	Ticks  []TickChange  `json:"ticks"`
+}
+
+/**
+ * Compares two snapshots of the same pool. Both pools' tick data providers must be entities.TickLister.
+ * @param before the earlier snapshot
+ * @param after the later snapshot
+ */
+func Diff(before, after *entities.Pool) (*PoolDiff, error) {
+	if !before.Token0.Equal(after.Token0) || !before.Token1.Equal(after.Token1) {
+		return nil, ErrDifferentPools
+	}
+	oldLister, ok := before.TickDataProvider.(entities.TickLister)
+	if !ok {
+		return nil, entities.ErrTicksNotListable
+	}
+	newLister, ok := after.TickDataProvider.(entities.TickLister)
+	if !ok {
+		return nil, entities.ErrTicksNotListable
+	}
+
+	d := &PoolDiff{Fields: []FieldChange{}, Ticks: []TickChange{}}
+	d.compare("sqrt_price_x64", before.SqrtRatioX64, after.SqrtRatioX64)
+	d.compare("tick_current", big.NewInt(int64(before.TickCurrent)), big.NewInt(int64(after.TickCurrent)))
+	d.compare("liquidity", before.Liquidity, after.Liquidity)
+	d.compare("fee", new(big.Int).SetUint64(before.Fee), new(big.Int).SetUint64(after.Fee))
+	d.compare("tick_spacing", big.NewInt(int64(before.TickSpacing)), big.NewInt(int64(after.TickSpacing)))
+	d.compare("protocol_fee_rate", new(big.Int).SetUint64(before.ProtocolFeeRate), new(big.Int).SetUint64(after.ProtocolFeeRate))
+
+	// both lists are sorted, so merge them
+	oldTicks, newTicks := oldLister.Ticks(), newLister.Ticks()
+	zero := entities.Tick{LiquidityNet: big.NewInt(0), LiquidityGross: big.NewInt(0)}
+	for i, j := 0, 0; i < len(oldTicks) || j < len(newTicks); {
+		switch {
+		case j == len(newTicks) || (i < len(oldTicks) && oldTicks[i].Index < newTicks[j].Index):
+			d.compareTick(TickRemoved, oldTicks[i], zero)
+			i++
+		case i == len(oldTicks) || newTicks[j].Index < oldTicks[i].Index:
+			d.compareTick(TickAdded, zero, newTicks[j])
+			j++
+		default:
+			if oldTicks[i].LiquidityNet.Cmp(newTicks[j].LiquidityNet) != 0 ||
+				oldTicks[i].LiquidityGross.Cmp(newTicks[j].LiquidityGross) != 0 {
+				d.compareTick(TickChanged, oldTicks[i], newTicks[j])
+			}
+			i++
+			j++
+		}
+	}
+	return d, nil
+}
+
+func (d *PoolDiff) compare(field string, before, after *big.Int) {
+	if before.Cmp(after) == 0 {
+		return
+	}
+	d.Fields = append(d.Fields, FieldChange{
+		Field: field,
+		Old:   before.String(),
+		New:   after.String(),
+		Delta: new(big.Int).Sub(after, before).String(),
+	})
+}
+
+func (d *PoolDiff) compareTick(kind string, before, after entities.Tick) {
+	index := before.Index
+	if kind == TickAdded {
+		index = after.Index
+	}
+	d.Ticks = append(d.Ticks, TickChange{
+		Index:             index,
+		Kind:              kind,
+		OldLiquidityNet:   before.LiquidityNet.String(),
+		NewLiquidityNet:   after.LiquidityNet.String(),
+		OldLiquidityGross: before.LiquidityGross.String(),
+		NewLiquidityGross: after.LiquidityGross.String(),
+	})
+}
+
+// Empty reports whether the snapshots are the same
+func (d *PoolDiff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.Ticks) == 0
+}
+
+// String renders the diff one change per line
+func (d *PoolDiff) String() string {
+	if d.Empty() {
+		return "no changes\n"
+	}
+	var b strings.Builder
+	for _, f := range d.Fields {
+		fmt.Fprintf(&b, "%s: %s -> %s (%s)\n", f.Field, f.Old, f.New, signed(f.Delta))
+	}
+	for _, t := range d.Ticks {
+		fmt.Fprintf(&b, "tick %d: %s, liquidity_net %s -> %s, liquidity_gross %s -> %s\n", t.Index, t.Kind,
+			t.OldLiquidityNet, t.NewLiquidityNet, t.OldLiquidityGross, t.NewLiquidityGross)
+	}
+	return b.String()
+}
+
+func signed(delta string) string {
+	if strings.HasPrefix(delta, "-") {
+		return delta
+	}
+	return "+" + delta
+}
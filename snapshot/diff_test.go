@@ -0,0 +1,68 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := newTestPool(SUI, USDC, constants.FeeMedium, 0)
+
+	d, err := Diff(before, before)
+	assert.NoError(t, err)
+	assert.True(t, d.Empty())
+	assert.Equal(t, "no changes\n", d.String())
+
+	// adds ticks -120 and 60, and liquidity to the existing tick -180
+	after, err := before.ApplyMint(-180, 60, big.NewInt(100))
+	assert.NoError(t, err)
+	after, err = after.ApplyMint(-120, 300, big.NewInt(5))
+	assert.NoError(t, err)
+	after, err = after.ApplyBurn(-180, 300, big.NewInt(7))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	d, err = Diff(before, after)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldChange{{Field: "liquidity", Old: "1000000000000000007", New: "1000000000000000105", Delta: "98"}}, d.Fields)
+	assert.Equal(t, []TickChange{
+		{Index: -180, Kind: TickChanged, OldLiquidityNet: "7", NewLiquidityNet: "100", OldLiquidityGross: "7", NewLiquidityGross: "100"},
+		{Index: -120, Kind: TickAdded, OldLiquidityNet: "0", NewLiquidityNet: "5", OldLiquidityGross: "0", NewLiquidityGross: "5"},
+		{Index: 60, Kind: TickAdded, OldLiquidityNet: "0", NewLiquidityNet: "-100", OldLiquidityGross: "0", NewLiquidityGross: "100"},
+		{Index: 300, Kind: TickChanged, OldLiquidityNet: "-7", NewLiquidityNet: "-5", OldLiquidityGross: "7", NewLiquidityGross: "5"},
+	}, d.Ticks)
+	assert.Equal(t, "liquidity: 1000000000000000007 -> 1000000000000000105 (+98)\n"+
+		"tick -180: changed, liquidity_net 7 -> 100, liquidity_gross 7 -> 100\n"+
+		"tick -120: added, liquidity_net 0 -> 5, liquidity_gross 0 -> 5\n"+
+		"tick 60: added, liquidity_net 0 -> -100, liquidity_gross 0 -> 100\n"+
+		"tick 300: changed, liquidity_net -7 -> -5, liquidity_gross 7 -> 5\n", d.String())
+
+	// the reverse diff removes the added ticks
+	d, err = Diff(after, before)
+	assert.NoError(t, err)
+	assert.Equal(t, "-98", d.Fields[0].Delta)
+	assert.Equal(t, TickRemoved, d.Ticks[1].Kind)
+	assert.Equal(t, "5", d.Ticks[1].OldLiquidityNet)
+
+	moved := newTestPool(SUI, USDC, constants.FeeMedium, -30)
+	sqrtRatioX64 := new(big.Int).Set(moved.SqrtRatioX64)
+	d, err = Diff(before, moved)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sqrt_price_x64", "tick_current"}, []string{d.Fields[0].Field, d.Fields[1].Field})
+	assert.Equal(t, "-30", d.Fields[1].Delta)
+	assert.Empty(t, d.Ticks)
+	assert.Equal(t, sqrtRatioX64, moved.SqrtRatioX64, "diffing leaves the pools untouched")
+
+	data, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"field":"tick_current","old":"0","new":"-30","delta":"-30"}`)
+	assert.Contains(t, string(data), `"ticks":[]`)
+
+	_, err = Diff(before, newTestPool(SUI, USDT, constants.FeeMedium, 0))
+	assert.ErrorIs(t, err, ErrDifferentPools)
+}
@@ -0,0 +1,340 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+)
+
+// readChunk is how much of a record payload is read at a time, so the buffer grows with the bytes actually read
+const readChunk = 64 << 10
+
+// Reader streams pools out of a snapshot
+type Reader struct {
+	r       *bufio.Reader
+	sized   sized // the source, when it knows how many bytes are left
+	version uint16
+	header  header
+	tokens  []*entities.Token
+	pools   uint64
+	buf     []byte
+	done    bool
+}
+
+// sized is implemented by in-memory sources such as *bytes.Reader and *bytes.Buffer
+type sized interface {
+	Len() int
+}
+
+/**
+ * Returns a reader of the snapshot in r, after reading and verifying its header
+ * @param r the source of the snapshot
+ */
+func NewReader(r io.Reader) (*Reader, error) {
+	sr := &Reader{r: bufio.NewReader(r)}
+	sr.sized, _ = r.(sized)
+
+	h := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(sr.r, h); err != nil {
+		return nil, truncated(err)
+	}
+	if string(h[:len(magic)]) != magic {
+		return nil, ErrInvalidMagic
+	}
+	sr.version = binary.LittleEndian.Uint16(h[len(magic):])
+	if sr.version == 0 || sr.version > Version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, sr.version)
+	}
+
+	fields := make([]byte, binary.LittleEndian.Uint16(h[len(magic)+2:]))
+	var sum [4]byte
+	if _, err := io.ReadFull(sr.r, fields); err != nil {
+		return nil, truncated(err)
+	}
+	if _, err := io.ReadFull(sr.r, sum[:]); err != nil {
+		return nil, truncated(err)
+	}
+	crc := crc32.Update(crc32.Checksum(h, castagnoli), castagnoli, fields)
+	if crc != binary.LittleEndian.Uint32(sum[:]) {
+		return nil, ErrChecksum
+	}
+	// fields added after version 1 follow the known ones and are ignored
+	if len(fields) >= 4 {
+		sr.header.flags = binary.LittleEndian.Uint32(fields)
+	}
+	return sr, nil
+}
+
+// Version returns the format version of the snapshot
+func (r *Reader) Version() uint16 {
+	return r.version
+}
+
+/**
+ * Returns the next pool of the snapshot and its object ID, or io.EOF after the last one
+ */
+func (r *Reader) Next() (string, *entities.Pool, error) {
+	for !r.done {
+		recordType, payload, err := r.record()
+		if err != nil {
+			return "", nil, err
+		}
+		switch recordType {
+		case recordToken:
+			token, err := decodeToken(payload)
+			if err != nil {
+				return "", nil, err
+			}
+			r.tokens = append(r.tokens, token)
+		case recordPool:
+			id, pool, err := r.decodePool(payload)
+			if err != nil {
+				return "", nil, err
+			}
+			r.pools++
+			return id, pool, nil
+		case recordEnd:
+			d := decoder{b: payload}
+			if count := d.uvarint(); d.err != nil || count != r.pools {
+				return "", nil, fmt.Errorf("%w: end record does not match %d pools read", ErrCorrupt, r.pools)
+			}
+			r.done = true
+		default:
+			// a record type added by a later writer
+		}
+	}
+	return "", nil, io.EOF
+}
+
+/**
+ * Reads every pool of a snapshot, keyed by object ID
+ * @param r the source of the snapshot
+ */
+func ReadAll(r io.Reader) (map[string]*entities.Pool, error) {
+	sr, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	pools := make(map[string]*entities.Pool)
+	for {
+		id, pool, err := sr.Next()
+		if err == io.EOF {
+			return pools, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		pools[id] = pool
+	}
+}
+
+func (r *Reader) record() (byte, []byte, error) {
+	recordType, err := r.r.ReadByte()
+	if err != nil {
+		// a snapshot ends with its end record, never at a record boundary
+		return 0, nil, truncated(err)
+	}
+	length, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return 0, nil, truncated(err)
+	}
+	// the length is not covered by the checksum yet, never trust it with an allocation
+	if length > MaxRecordSize {
+		return 0, nil, fmt.Errorf("%w: record of %d bytes exceeds the maximum of %d", ErrCorrupt, length, MaxRecordSize)
+	}
+	if r.sized != nil {
+		if left := uint64(r.r.Buffered() + r.sized.Len()); length+4 > left {
+			return 0, nil, &recordLengthError{length: length, left: left}
+		}
+	}
+	payload, err := r.payload(int(length))
+	if err != nil {
+		return 0, nil, err
+	}
+	var sum [4]byte
+	if _, err := io.ReadFull(r.r, sum[:]); err != nil {
+		return 0, nil, truncated(err)
+	}
+	crc := crc32.Update(crc32.Update(0, castagnoli, []byte{recordType}), castagnoli, payload)
+	if crc != binary.LittleEndian.Uint32(sum[:]) {
+		return 0, nil, ErrChecksum
+	}
+	return recordType, payload, nil
+}
+
+// payload reads a record payload of n bytes into the reused buffer, growing it only as the bytes arrive
+func (r *Reader) payload(n int) ([]byte, error) {
+	buf := r.buf[:0]
+	for len(buf) < n {
+		chunk := n - len(buf)
+		if chunk > readChunk {
+			chunk = readChunk
+		}
+		if cap(buf)-len(buf) < chunk {
+			buf = append(buf, make([]byte, chunk)...)[:len(buf)]
+		}
+		read, err := io.ReadFull(r.r, buf[len(buf):len(buf)+chunk])
+		buf = buf[:len(buf)+read]
+		if err != nil {
+			r.buf = buf
+			return nil, truncated(err)
+		}
+	}
+	r.buf = buf
+	return buf, nil
+}
+
+func decodeToken(payload []byte) (*entities.Token, error) {
+	d := decoder{b: payload}
+	chainID := d.uvarint()
+	address := d.string()
+	decimals := d.uvarint()
+	symbol := d.string()
+	name := d.string()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if decimals >= 255 {
+		return nil, fmt.Errorf("%w: token %s has %d decimals", ErrCorrupt, address, decimals)
+	}
+	return entities.NewToken(uint(chainID), address, uint(decimals), symbol, name), nil
+}
+
+func (r *Reader) decodePool(payload []byte) (string, *entities.Pool, error) {
+	d := decoder{b: payload}
+	id := d.string()
+	token0 := d.uvarint()
+	token1 := d.uvarint()
+	fee := d.uvarint()
+	tickSpacing := int(d.varint())
+	sqrtRatioX64 := d.bigInt()
+	liquidity := d.bigInt()
+	tickCurrent := int(d.varint())
+	protocolFeeRate := d.uvarint()
+
+	count := d.uvarint()
+	if d.err == nil && count > uint64(len(d.b)) {
+		// every tick takes more than a byte, do not trust the count for the allocation
+		d.err = ErrCorrupt
+	}
+	var ticks []entities.Tick
+	if d.err == nil {
+		ticks = make([]entities.Tick, 0, count)
+	}
+	index := 0
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		index += int(d.varint())
+		ticks = append(ticks, entities.Tick{Index: index, LiquidityGross: d.bigInt(), LiquidityNet: d.bigInt()})
+	}
+	if d.err != nil {
+		return "", nil, d.err
+	}
+	if token0 >= uint64(len(r.tokens)) || token1 >= uint64(len(r.tokens)) {
+		return "", nil, fmt.Errorf("%w: pool %s refers to an unknown token", ErrCorrupt, id)
+	}
+
+	provider, err := entities.NewTickListDataProvider(ticks, tickSpacing)
+	if err != nil {
+		return "", nil, fmt.Errorf("pool %s: %w", id, err)
+	}
+	pool, err := entities.NewPool(r.tokens[token0], r.tokens[token1], fee, tickSpacing, sqrtRatioX64, liquidity, tickCurrent, provider)
+	if err != nil {
+		return "", nil, fmt.Errorf("pool %s: %w", id, err)
+	}
+	pool.ProtocolFeeRate = protocolFeeRate
+	return id, pool, nil
+}
+
+// recordLengthError is a record longer than what is left of the snapshot, which is either truncated or has a corrupt
+// record length, so it is both ErrTruncated and ErrCorrupt
+type recordLengthError struct {
+	length, left uint64
+}
+
+func (e *recordLengthError) Error() string {
+	return fmt.Sprintf("snapshot is truncated or corrupt: record of %d bytes with %d bytes left", e.length, e.left)
+}
+
+func (e *recordLengthError) Is(target error) bool {
+	return target == ErrTruncated || target == ErrCorrupt
+}
+
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	}
+	return err
+}
+
+// decoder reads the fields of a record payload, remembering the first error
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = ErrCorrupt
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = ErrCorrupt
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.b)) {
+		d.err = ErrCorrupt
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+func (d *decoder) bigInt() *big.Int {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.b) == 0 {
+		d.err = ErrCorrupt
+		return nil
+	}
+	sign := d.b[0]
+	d.b = d.b[1:]
+	n := new(big.Int).SetBytes(d.bytes())
+	if sign == 1 {
+		n.Neg(n)
+	}
+	return n
+}
@@ -0,0 +1,61 @@
+// Package snapshot implements a compact, versioned binary format for snapshots of many pools.
+//
+// A snapshot starts with a header:
+//
+//	magic      [8]byte  "CLMMSNAP"
+//	version    uint16   the format version, bumped only for incompatible changes
+//	headerLen  uint16   the length of the header fields that follow
+//	fields     [headerLen]byte
+//	checksum   uint32   CRC-32C of everything before it
+//
+// followed by a stream of records:
+//
+//	type       byte
+//	length     uvarint
+//	payload    [length]byte
+//	checksum   uint32   CRC-32C of type and payload
+//
+// Tokens are written once, the first time a pool refers to them, and pools refer to tokens by their position in the
+// stream. The stream ends with an end record holding the number of pools, which tells a complete snapshot from a
+// truncated one. Readers skip header fields and record types they do not know, so compatible additions do not need
+// a new version. All integers are little endian, signed varints are zig-zag encoded.
+package snapshot
+
+import (
+	"errors"
+	"hash/crc32"
+)
+
+const (
+	magic = "CLMMSNAP"
+
+	// Version is the format version written by this package
+	Version uint16 = 1
+
+	// MaxRecordSize is the largest record payload, in bytes. A pool with an initialized tick at every tick of a
+	// tick spacing of 1 takes about 40MB.
+	MaxRecordSize = 64 << 20
+)
+
+const (
+	recordEnd   byte = 0
+	recordToken byte = 1
+	recordPool  byte = 2
+)
+
+var (
+	ErrInvalidMagic       = errors.New("not a pool snapshot")
+	ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+	ErrChecksum           = errors.New("snapshot checksum mismatch")
+	ErrTruncated          = errors.New("snapshot is truncated")
+	ErrCorrupt            = errors.New("snapshot is corrupt")
+	ErrClosed             = errors.New("snapshot writer is closed")
+	ErrRecordTooLarge     = errors.New("snapshot record is too large")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// header holds the header fields of version 1, which are reserved flags for now
+type header struct {
+	flags uint32
+}
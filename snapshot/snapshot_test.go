@@ -0,0 +1,198 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+	"github.com/mythril-labs/clmm-sui-sdk/internal/testpools"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	SUI  = testpools.SUI
+	USDC = testpools.USDC
+	USDT = testpools.USDT
+)
+
+// newTestPool returns a full range pool with a few more ticks and a protocol fee, so that every field is written
+func newTestPool(token0, token1 *entities.Token, fee uint64, tick int) *entities.Pool {
+	spacing := constants.TickSpacings[fee]
+	ticks := testpools.FullRangeTicks(fee, big.NewInt(1e18))
+	ticks = []entities.Tick{
+		ticks[0],
+		{Index: -spacing * 3, LiquidityNet: big.NewInt(7), LiquidityGross: big.NewInt(7)},
+		{Index: spacing * 5, LiquidityNet: big.NewInt(-7), LiquidityGross: big.NewInt(7)},
+		ticks[1],
+	}
+	pool := testpools.New(token0, token1, fee, tick, big.NewInt(1e18+7), ticks)
+	pool.ProtocolFeeRate = 2000
+	return pool
+}
+
+func writeSnapshot(t testing.TB, pools map[string]*entities.Pool, ids []string) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		if err := w.WritePool(id, pools[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	pools := map[string]*entities.Pool{
+		"0x1": newTestPool(SUI, USDC, constants.FeeMedium, 0),
+		"0x2": newTestPool(SUI, USDC, constants.FeeLow, -20),
+		"0x3": newTestPool(USDC, USDT, constants.FeeLowest, 2),
+	}
+	data := writeSnapshot(t, pools, []string{"0x1", "0x2", "0x3"})
+
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, Version, r.Version())
+	read := 0
+	for {
+		id, pool, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		read++
+		want := pools[id]
+		assert.True(t, want.Token0.Equal(pool.Token0))
+		assert.True(t, want.Token1.Equal(pool.Token1))
+		assert.Equal(t, want.Token0.Symbol(), pool.Token0.Symbol())
+		assert.Equal(t, want.Token1.Decimals(), pool.Token1.Decimals())
+		assert.Equal(t, want.Fee, pool.Fee)
+		assert.Equal(t, want.TickSpacing, pool.TickSpacing)
+		assert.Equal(t, want.SqrtRatioX64, pool.SqrtRatioX64)
+		assert.Equal(t, want.Liquidity, pool.Liquidity)
+		assert.Equal(t, want.TickCurrent, pool.TickCurrent)
+		assert.Equal(t, want.ProtocolFeeRate, pool.ProtocolFeeRate)
+		assert.Equal(t, want.TickDataProvider.(entities.TickLister).Ticks(), pool.TickDataProvider.(entities.TickLister).Ticks())
+	}
+	assert.Equal(t, 3, read)
+	assert.Len(t, r.tokens, 3, "tokens shared between pools are written once")
+
+	all, err := ReadAll(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestCorruptSnapshots(t *testing.T) {
+	pools := map[string]*entities.Pool{"0x1": newTestPool(SUI, USDC, constants.FeeMedium, 0)}
+	data := writeSnapshot(t, pools, []string{"0x1"})
+
+	_, err := ReadAll(bytes.NewReader([]byte("NOTASNAPSHOT")))
+	assert.ErrorIs(t, err, ErrInvalidMagic)
+
+	future := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint16(future[len(magic):], Version+1)
+	_, err = ReadAll(bytes.NewReader(future))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	flipped := append([]byte(nil), data...)
+	flipped[len(flipped)/2] ^= 0xff
+	_, err = ReadAll(bytes.NewReader(flipped))
+	assert.ErrorIs(t, err, ErrChecksum)
+
+	for _, n := range []int{5, len(data) / 2, len(data) - 1} {
+		_, err = ReadAll(bytes.NewReader(data[:n]))
+		assert.ErrorIs(t, err, ErrTruncated, "truncated at %d", n)
+	}
+
+	// a snapshot without its end record is truncated, even at a record boundary
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf)
+	assert.NoError(t, w.WritePool("0x1", pools["0x1"]))
+	assert.NoError(t, w.w.Flush())
+	_, err = ReadAll(bytes.NewReader(buf.Bytes()))
+	assert.ErrorIs(t, err, ErrTruncated)
+
+	assert.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WritePool("0x2", pools["0x1"]), ErrClosed)
+}
+
+func TestCorruptRecordLength(t *testing.T) {
+	data := writeSnapshot(t, map[string]*entities.Pool{"0x1": newTestPool(SUI, USDC, constants.FeeMedium, 0)}, []string{"0x1"})
+
+	// the first record starts after the header, its length is a single byte uvarint
+	start := len(magic) + 2 + 2 + 4 + 4
+	withLength := func(length uint64) []byte {
+		corrupt := append([]byte(nil), data[:start+1]...)
+		corrupt = appendUvarint(corrupt, length)
+		return append(corrupt, data[start+2:]...)
+	}
+
+	for _, length := range []uint64{1<<63 + 5, MaxRecordSize + 1, uint64(len(data))} {
+		_, err := ReadAll(bytes.NewReader(withLength(length)))
+		assert.ErrorIs(t, err, ErrCorrupt, "length %d", length)
+	}
+
+	// a stream does not know how many bytes are left, it runs out of them before allocating the whole length
+	_, err := ReadAll(io.MultiReader(bytes.NewReader(withLength(1<<63 + 5))))
+	assert.ErrorIs(t, err, ErrCorrupt)
+	_, err = ReadAll(io.MultiReader(bytes.NewReader(withLength(MaxRecordSize))))
+	assert.ErrorIs(t, err, ErrTruncated)
+}
+
+func TestForwardCompatibility(t *testing.T) {
+	pool := newTestPool(SUI, USDC, constants.FeeMedium, 0)
+
+	// a later writer with an extra header field and an extra record type
+	var buf bytes.Buffer
+	fields := []byte{0, 0, 0, 0, 42, 42}
+	h := []byte(magic)
+	h = appendUint16(h, Version)
+	h = appendUint16(h, uint16(len(fields)))
+	h = append(h, fields...)
+	h = appendUint32(h, crc32.Checksum(h, castagnoli))
+	buf.Write(h)
+	w := &Writer{w: bufio.NewWriter(&buf), tokens: make(map[tokenKey]uint64)}
+	assert.NoError(t, w.record(99, []byte("from the future")))
+	assert.NoError(t, w.WritePool("0x1", pool))
+	assert.NoError(t, w.Close())
+
+	pools, err := ReadAll(&buf)
+	assert.NoError(t, err)
+	if assert.Contains(t, pools, "0x1") {
+		assert.Equal(t, pool.SqrtRatioX64, pools["0x1"].SqrtRatioX64)
+	}
+}
+
+func BenchmarkReadAll(b *testing.B) {
+	pools := make(map[string]*entities.Pool)
+	var ids []string
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("0x%x", i)
+		pools[id] = newTestPool(SUI, USDC, constants.FeeMedium, i%500-250)
+		ids = append(ids, id)
+	}
+	data := writeSnapshot(b, pools, ids)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ReadAll(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/big"
+
+	"github.com/mythril-labs/clmm-sui-sdk/entities"
+)
+
+// Writer streams pools into a snapshot
+type Writer struct {
+	w      *bufio.Writer
+	tokens map[tokenKey]uint64
+	pools  uint64
+	buf    []byte
+	err    error
+	closed bool
+}
+
+type tokenKey struct {
+	chainID uint
+	address string
+}
+
+/**
+ * Returns a writer that streams a snapshot to w, starting with the header
+ * @param w the destination of the snapshot
+ */
+func NewWriter(w io.Writer) (*Writer, error) {
+	sw := &Writer{w: bufio.NewWriter(w), tokens: make(map[tokenKey]uint64)}
+
+	var fields [4]byte
+	binary.LittleEndian.PutUint32(fields[:], header{}.flags)
+
+	h := []byte(magic)
+	h = appendUint16(h, Version)
+	h = appendUint16(h, uint16(len(fields)))
+	h = append(h, fields[:]...)
+	h = appendUint32(h, crc32.Checksum(h, castagnoli))
+	if _, err := sw.w.Write(h); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+/**
+ * Writes a pool, preceded by any of its tokens not written before. The pool's tick data provider must be an
+ * entities.TickLister.
+ * @param id the object ID of the pool
+ * @param pool the pool to write
+ */
+func (w *Writer) WritePool(id string, pool *entities.Pool) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	lister, ok := pool.TickDataProvider.(entities.TickLister)
+	if !ok {
+		return entities.ErrTicksNotListable
+	}
+
+	token0, err := w.token(pool.Token0)
+	if err != nil {
+		return err
+	}
+	token1, err := w.token(pool.Token1)
+	if err != nil {
+		return err
+	}
+
+	b := w.buf[:0]
+	b = appendString(b, id)
+	b = appendUvarint(b, token0)
+	b = appendUvarint(b, token1)
+	b = appendUvarint(b, pool.Fee)
+	b = appendVarint(b, int64(pool.TickSpacing))
+	b = appendBigInt(b, pool.SqrtRatioX64)
+	b = appendBigInt(b, pool.Liquidity)
+	b = appendVarint(b, int64(pool.TickCurrent))
+	b = appendUvarint(b, pool.ProtocolFeeRate)
+
+	// tick indexes are sorted, so they are delta encoded
+	ticks := lister.Ticks()
+	b = appendUvarint(b, uint64(len(ticks)))
+	previous := 0
+	for _, tick := range ticks {
+		b = appendVarint(b, int64(tick.Index-previous))
+		b = appendBigInt(b, tick.LiquidityGross)
+		b = appendBigInt(b, tick.LiquidityNet)
+		previous = tick.Index
+	}
+	w.buf = b
+
+	if err := w.record(recordPool, b); err != nil {
+		return err
+	}
+	w.pools++
+	return nil
+}
+
+/**
+ * Writes the end record and flushes the snapshot. It does not close the underlying writer.
+ */
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.record(recordEnd, appendUvarint(nil, w.pools)); err != nil {
+		return err
+	}
+	w.closed = true
+	return w.w.Flush()
+}
+
+// token returns the position of a token in the stream, writing it first if needed
+func (w *Writer) token(token *entities.Token) (uint64, error) {
+	key := tokenKey{chainID: token.ChainId(), address: token.Address}
+	if index, ok := w.tokens[key]; ok {
+		return index, nil
+	}
+
+	var b []byte
+	b = appendUvarint(b, uint64(token.ChainId()))
+	b = appendString(b, token.Address)
+	b = appendUvarint(b, uint64(token.Decimals()))
+	b = appendString(b, token.Symbol())
+	b = appendString(b, token.Name())
+	if err := w.record(recordToken, b); err != nil {
+		return 0, err
+	}
+
+	index := uint64(len(w.tokens))
+	w.tokens[key] = index
+	return index, nil
+}
+
+func (w *Writer) record(recordType byte, payload []byte) error {
+	if len(payload) > MaxRecordSize {
+		// the stream stays valid, the record is not written
+		return fmt.Errorf("%w: %d bytes", ErrRecordTooLarge, len(payload))
+	}
+	var head [1 + binary.MaxVarintLen64]byte
+	head[0] = recordType
+	n := 1 + binary.PutUvarint(head[1:], uint64(len(payload)))
+
+	crc := crc32.Update(crc32.Update(0, castagnoli, head[:1]), castagnoli, payload)
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], crc)
+
+	for _, part := range [][]byte{head[:n], payload, sum[:]} {
+		if _, err := w.w.Write(part); err != nil {
+			w.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendBigInt encodes a big integer as its sign and its big endian magnitude
+func appendBigInt(b []byte, n *big.Int) []byte {
+	var sign byte
+	if n.Sign() < 0 {
+		sign = 1
+	}
+	magnitude := n.Bytes()
+	b = append(b, sign)
+	b = appendUvarint(b, uint64(len(magnitude)))
+	return append(b, magnitude...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+var ErrInvalidTickSpacing = errors.New("invalid tick spacing")
+
+// SqrtRatioTable is a lookup table of the sqrt ratios at every usable tick of a tick spacing, i.e. every
+// multiple of the spacing between MinTick and MaxTick. It also holds the ticks just below the multiples of a word of
+// 256 usable ticks, where swaps from token1 to token0 stop when they cross a word without initialized ticks. The
+// table is built lazily on first use and is safe for concurrent use. Other ticks fall back to GetSqrtRatioAtTick.
+//
+// Memory and build time grow linearly with the number of usable ticks: a spacing of 1 holds ~887k entries
+// (tens of MB, built in about a second), a spacing of 60 holds ~15k entries (about 1MB, built in tens of ms).
+// Lookups are a copy of a precomputed value instead of up to 20 big-int multiplications, see the
+// BenchmarkSqrtRatioTable* benchmarks and BenchmarkSwapWithSqrtRatioTable in entities for the numbers on a given
+// machine.
+type SqrtRatioTable struct {
+	tickSpacing int
+	minTick     int // the smallest multiple of tickSpacing within [MinTick, MaxTick]
+	minWord     int // the smallest word whose boundary below, word*wordTicks - 1, is within [MinTick, MaxTick]
+
+	once       sync.Once
+	ratios     []*big.Int
+	boundaries []*big.Int // the sqrt ratios at word*wordTicks - 1 from minWord on
+}
+
+var sqrtRatioTables sync.Map // tick spacing -> *SqrtRatioTable
+
+// logSqrt10001 is the natural log of the growth of the sqrt ratio per tick
+var logSqrt10001 = math.Log1p(0.0001) / 2
+
+// tickEstimateMargin is how close to a tick boundary an estimated tick offset has to be to be checked exactly, on top
+// of the error of the Q64.64 sqrt ratios themselves. A sqrt ratio is off by a unit or two, up to 4e4/ratio ticks, so
+// 1e6/ratio is added, which dominates at the lowest prices. float64 adds about 1e-12 ticks.
+const tickEstimateMargin = 1e-6
+
+/**
+ * Returns a new, not yet built, lookup table for the given tick spacing
+ * @param tickSpacing the spacing of the usable ticks to precompute
+ */
+func NewSqrtRatioTable(tickSpacing int) (*SqrtRatioTable, error) {
+	if tickSpacing <= 0 {
+		return nil, ErrInvalidTickSpacing
+	}
+	return &SqrtRatioTable{
+		tickSpacing: tickSpacing,
+		minTick:     MinTick / tickSpacing * tickSpacing, // truncation rounds towards zero, i.e. up
+		minWord:     (MinTick + 1) / (tickSpacing << 8),
+	}, nil
+}
+
+// wordTicks returns the number of ticks in a word of the tick bitmap
+func (t *SqrtRatioTable) wordTicks() int {
+	return t.tickSpacing << 8
+}
+
+/**
+ * Returns the process wide lookup table for the given tick spacing, so pools with the same spacing share one table
+ * @param tickSpacing the spacing of the usable ticks to precompute
+ */
+func SqrtRatioTableFor(tickSpacing int) (*SqrtRatioTable, error) {
+	if t, ok := sqrtRatioTables.Load(tickSpacing); ok {
+		return t.(*SqrtRatioTable), nil
+	}
+	t, err := NewSqrtRatioTable(tickSpacing)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := sqrtRatioTables.LoadOrStore(tickSpacing, t)
+	return actual.(*SqrtRatioTable), nil
+}
+
+// TickSpacing returns the spacing of the precomputed ticks
+func (t *SqrtRatioTable) TickSpacing() int {
+	return t.tickSpacing
+}
+
+// Len returns the number of precomputed usable ticks, building the table if needed
+func (t *SqrtRatioTable) Len() int {
+	t.build()
+	return len(t.ratios)
+}
+
+func (t *SqrtRatioTable) build() {
+	t.once.Do(func() {
+		ratios := make([]*big.Int, 0, (MaxTick-t.minTick)/t.tickSpacing+1)
+		for tick := t.minTick; tick <= MaxTick; tick += t.tickSpacing {
+			// cannot fail, every tick is within bounds
+			ratio, _ := GetSqrtRatioAtTick(tick)
+			ratios = append(ratios, ratio)
+		}
+		var boundaries []*big.Int
+		for tick := t.minWord*t.wordTicks() - 1; tick <= MaxTick; tick += t.wordTicks() {
+			ratio, _ := GetSqrtRatioAtTick(tick)
+			boundaries = append(boundaries, ratio)
+		}
+		t.ratios, t.boundaries = ratios, boundaries
+	})
+}
+
+/**
+ * Returns the sqrt ratio as a Q64.64 for the given tick, see GetSqrtRatioAtTick
+ * @param tick the tick for which to look up the sqrt ratio
+ */
+func (t *SqrtRatioTable) GetSqrtRatioAtTick(tick int) (*big.Int, error) {
+	if tick < MinTick || tick > MaxTick {
+		return nil, ErrInvalidTick
+	}
+	t.build()
+	// callers own the returned value, never hand out the shared entry
+	if (tick-t.minTick)%t.tickSpacing == 0 {
+		return new(big.Int).Set(t.ratios[(tick-t.minTick)/t.tickSpacing]), nil
+	}
+	if (tick+1)%t.wordTicks() == 0 {
+		return new(big.Int).Set(t.boundaries[(tick+1)/t.wordTicks()-t.minWord]), nil
+	}
+	return GetSqrtRatioAtTick(tick)
+}
+
+/**
+ * Returns the tick corresponding to a given sqrt ratio, see GetTickAtSqrtRatio. The table is binary searched for the
+ * usable tick at or below the ratio, and the offset from there is estimated from the ratio of the two sqrt ratios,
+ * which is checked exactly only when it lands next to a tick boundary.
+ * @param sqrtRatioX64 the sqrt ratio as a Q64.64 for which to compute the tick
+ */
+func (t *SqrtRatioTable) GetTickAtSqrtRatio(sqrtRatioX64 *big.Int) (int, error) {
+	if sqrtRatioX64.Cmp(MinSqrtRatio) < 0 || sqrtRatioX64.Cmp(MaxSqrtRatio) >= 0 {
+		return 0, ErrInvalidSqrtRatio
+	}
+	t.build()
+
+	// index of the largest entry that is less than or equal to the sqrt ratio
+	index := sort.Search(len(t.ratios), func(i int) bool {
+		return t.ratios[i].Cmp(sqrtRatioX64) > 0
+	}) - 1
+	if index < 0 {
+		return GetTickAtSqrtRatio(sqrtRatioX64)
+	}
+	tick := t.minTick + index*t.tickSpacing
+	entry := t.ratios[index]
+	if t.tickSpacing == 1 || entry.Cmp(sqrtRatioX64) == 0 {
+		return tick, nil
+	}
+
+	// the sqrt ratio grows by sqrt(1.0001) per tick, so the offset from the entry is log(ratio / entry) in those steps
+	excess, _ := new(big.Float).SetInt(new(big.Int).Sub(sqrtRatioX64, entry)).Float64()
+	base, _ := new(big.Float).SetInt(entry).Float64()
+	offset := math.Log1p(excess/base) / logSqrt10001
+	nearest := math.Round(offset)
+	if math.Abs(offset-nearest) > tickEstimateMargin+1e6/base {
+		return tick + int(offset), nil
+	}
+
+	// too close to the boundary of tick + nearest to tell the side from the estimate
+	tick += int(nearest)
+	boundary, err := t.GetSqrtRatioAtTick(tick)
+	if err != nil {
+		return 0, err
+	}
+	if boundary.Cmp(sqrtRatioX64) > 0 {
+		tick--
+	}
+	return tick, nil
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/mythril-labs/clmm-sui-sdk/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqrtRatioTable(t *testing.T) {
+	_, err := NewSqrtRatioTable(0)
+	assert.ErrorIs(t, err, ErrInvalidTickSpacing)
+
+	table, err := NewSqrtRatioTable(60)
+	assert.NoError(t, err)
+
+	_, err = table.GetSqrtRatioAtTick(MinTick - 1)
+	assert.ErrorIs(t, err, ErrInvalidTick, "tick too small")
+	_, err = table.GetSqrtRatioAtTick(MaxTick + 1)
+	assert.ErrorIs(t, err, ErrInvalidTick, "tick too large")
+
+	// usable ticks, word boundaries below multiples of 256 * 60 and other ticks
+	for _, tick := range []int{MinTick, -443580, -430081, -97680, -15361, -61, -60, -1, 0, 1, 60, 15359, 97674, 430079, 443580, MaxTick} {
+		want, _ := GetSqrtRatioAtTick(tick)
+		got, err := table.GetSqrtRatioAtTick(tick)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, "tick %d", tick)
+	}
+
+	// returned values are copies
+	r0, _ := table.GetSqrtRatioAtTick(0)
+	r0.SetInt64(1)
+	r0, _ = table.GetSqrtRatioAtTick(0)
+	assert.Equal(t, constants.Q64, r0)
+
+	shared, err := SqrtRatioTableFor(60)
+	assert.NoError(t, err)
+	again, _ := SqrtRatioTableFor(60)
+	assert.Same(t, shared, again, "tables are shared per tick spacing")
+}
+
+func TestSqrtRatioTableGetTickAtSqrtRatio(t *testing.T) {
+	for _, spacing := range []int{1, 60, 200} {
+		table, _ := SqrtRatioTableFor(spacing)
+
+		_, err := table.GetTickAtSqrtRatio(new(big.Int).Sub(MinSqrtRatio, constants.One))
+		assert.ErrorIs(t, err, ErrInvalidSqrtRatio)
+		_, err = table.GetTickAtSqrtRatio(MaxSqrtRatio)
+		assert.ErrorIs(t, err, ErrInvalidSqrtRatio)
+
+		rnd := rand.New(rand.NewSource(1))
+		span := new(big.Int).Sub(MaxSqrtRatio, MinSqrtRatio)
+		samples := []*big.Int{MinSqrtRatio, new(big.Int).Sub(MaxSqrtRatio, constants.One), constants.Q64}
+		for i := 0; i < 200; i++ {
+			// sample uniformly in log space, otherwise nearly every sample lands at the top of the range
+			tick := rnd.Intn(MaxTick-MinTick) + MinTick
+			ratio, _ := GetSqrtRatioAtTick(tick)
+			samples = append(samples,
+				ratio,
+				new(big.Int).Sub(ratio, constants.One),
+				new(big.Int).Add(ratio, big.NewInt(rnd.Int63n(1000)+1)),
+				offTickRatio(rnd, tick),
+			)
+		}
+		samples = append(samples, new(big.Int).Add(MinSqrtRatio, new(big.Int).Rand(rnd, span)))
+
+		for _, sample := range samples {
+			if sample.Cmp(MaxSqrtRatio) >= 0 {
+				continue
+			}
+			want, err := GetTickAtSqrtRatio(sample)
+			assert.NoError(t, err)
+			got, err := table.GetTickAtSqrtRatio(sample)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "spacing %d sqrt ratio %s", spacing, sample)
+		}
+	}
+}
+
+// offTickRatio returns a sqrt ratio strictly between the sqrt ratios of tick and tick + 1
+func offTickRatio(rnd *rand.Rand, tick int) *big.Int {
+	lower, _ := GetSqrtRatioAtTick(tick)
+	upper, _ := GetSqrtRatioAtTick(tick + 1)
+	width := new(big.Int).Sub(upper, lower)
+	return new(big.Int).Add(lower, new(big.Int).Add(constants.One, new(big.Int).Rand(rnd, new(big.Int).Sub(width, constants.One))))
+}
+
+var benchmarkTicks = func() []int {
+	rnd := rand.New(rand.NewSource(1))
+	ticks := make([]int, 1024)
+	for i := range ticks {
+		ticks[i] = (rnd.Intn(MaxTick-MinTick) + MinTick) / 60 * 60
+	}
+	return ticks
+}()
+
+func BenchmarkGetSqrtRatioAtTick(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = GetSqrtRatioAtTick(benchmarkTicks[i%len(benchmarkTicks)])
+	}
+}
+
+func BenchmarkSqrtRatioTableLookup(b *testing.B) {
+	table, _ := NewSqrtRatioTable(60)
+	table.Len()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = table.GetSqrtRatioAtTick(benchmarkTicks[i%len(benchmarkTicks)])
+	}
+}
+
+func BenchmarkSqrtRatioTableBuild(b *testing.B) {
+	for _, spacing := range []int{1, 10, 60, 200} {
+		b.Run(strconv.Itoa(spacing), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				table, _ := NewSqrtRatioTable(spacing)
+				table.Len()
+			}
+		})
+	}
+}
+
+func BenchmarkGetTickAtSqrtRatio(b *testing.B) {
+	// swaps end between ticks, so most lookups are off the usable ticks of the table
+	rnd := rand.New(rand.NewSource(1))
+	ratios := make([]*big.Int, len(benchmarkTicks))
+	for i, tick := range benchmarkTicks {
+		ratios[i] = offTickRatio(rnd, tick+rnd.Intn(60))
+	}
+	b.Run("math", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = GetTickAtSqrtRatio(ratios[i%len(ratios)])
+		}
+	})
+	b.Run("table", func(b *testing.B) {
+		table, _ := NewSqrtRatioTable(60)
+		table.Len()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = table.GetTickAtSqrtRatio(ratios[i%len(ratios)])
+		}
+	})
+}